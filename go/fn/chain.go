@@ -0,0 +1,124 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// StepTag is the Result tag of the name of the StepChain step which made the result.
+const StepTag = "fn.kpt.dev/step"
+
+// ErrorPolicy is what a StepChain does when a step returns an error.
+type ErrorPolicy string
+
+const (
+	// StopOnError stops the StepChain at the first error, and returns it. This is what Chain does.
+	StopOnError ErrorPolicy = "stop"
+	// ContinueOnError reports the error of a step as an Error result, and runs the next steps. The StepChain fails
+	// without error if a step returns an error.
+	ContinueOnError ErrorPolicy = "continue"
+)
+
+// ChainStep is a named step of a StepChain.
+type ChainStep struct {
+	// Name tags the results of the step with StepTag. It defaults to the index of the step.
+	Name string
+	// Processor processes the ResourceList.
+	Processor ResourceListProcessor
+	// Skip skips the step if it returns true, e.g. when no item is of the kind the step is about. It may be nil.
+	Skip func(rl *ResourceList) bool
+}
+
+var _ ResourceListProcessor = StepChain{}
+
+// StepChain is a configurable Chain, e.g.
+//
+//	fn.AsMain(fn.StepChain{
+//		Steps: []fn.ChainStep{
+//			{Name: "defaults", Processor: fn.ResourceListProcessorFunc(setDefaults)},
+//			{Name: "validate", Processor: fn.ResourceListProcessorFunc(validate)},
+//		},
+//		StopOnFailure: true,
+//	})
+//
+// The results of each step, including the Results (or *Result) it returns as error, are tagged with the StepTag of
+// the step, so that a multi-step function tells which step made which result. Like Chain, it stops with the cause
+// of the ResourceList context when the context is done.
+type StepChain struct {
+	Steps []ChainStep
+	// ErrorPolicy defaults to StopOnError.
+	ErrorPolicy ErrorPolicy
+	// StopOnFailure stops the StepChain after the first step which fails (returns success=false).
+	StopOnFailure bool
+}
+
+// Process runs the steps in order. It fails if a step fails or returns an error.
+func (c StepChain) Process(rl *ResourceList) (bool, error) {
+	switch c.ErrorPolicy {
+	case "", StopOnError, ContinueOnError:
+	default:
+		return false, fmt.Errorf("unknown error policy %q, expect %q or %q", c.ErrorPolicy, StopOnError,
+			ContinueOnError)
+	}
+	success := true
+	for i, step := range c.Steps {
+		if err := contextError(rl); err != nil {
+			return false, err
+		}
+		name := step.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		if step.Skip != nil && step.Skip(rl) {
+			rl.Results = append(rl.Results, &Result{
+				Message:  fmt.Sprintf("step %q is skipped", name),
+				Severity: Info,
+				Tags:     map[string]string{StepTag: name},
+			})
+			continue
+		}
+		p := Use(step.Processor, TagResults(map[string]string{StepTag: name}))
+		s, err := traceStep(rl, "StepChain", i, step.Processor, p.Process)
+		if err != nil {
+			if isContextError(err) {
+				return false, err
+			}
+			if c.ErrorPolicy != ContinueOnError {
+				switch err.(type) {
+				case Results, *Result:
+					return false, err
+				}
+				return false, fmt.Errorf("step %q: %w", name, err)
+			}
+			if !addResults(rl, err) {
+				rl.Results = append(rl.Results, &Result{
+					Message:  fmt.Sprintf("step %q: %v", name, err),
+					Severity: Error,
+					Tags:     map[string]string{StepTag: name},
+				})
+			}
+			s = false
+		}
+		if !s {
+			success = false
+			if c.StopOnFailure {
+				break
+			}
+		}
+	}
+	return success, nil
+}
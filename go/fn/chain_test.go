@@ -0,0 +1,157 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepChain(t *testing.T) {
+	info := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Infof("ok")
+		return true, nil
+	})
+	failure := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Warningf("failed")
+		return false, nil
+	})
+	results := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		return false, Results{GeneralResult("invalid", Error)}
+	})
+	plainErr := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		return false, fmt.Errorf("broken")
+	})
+	step := func(name string, p ResourceListProcessor) ChainStep {
+		return ChainStep{Name: name, Processor: p}
+	}
+	tagged := func(message string, severity Severity, step string) *Result {
+		return &Result{Message: message, Severity: severity, Tags: map[string]string{StepTag: step}}
+	}
+
+	testcases := []struct {
+		name            string
+		chain           StepChain
+		expectedSuccess bool
+		expectedErr     string
+		expectedResults Results
+	}{
+		{
+			name:            "success",
+			chain:           StepChain{Steps: []ChainStep{step("first", info), {Processor: info}}},
+			expectedSuccess: true,
+			expectedResults: Results{tagged("ok", Info, "first"), tagged("ok", Info, "1")},
+		},
+		{
+			name:            "failure",
+			chain:           StepChain{Steps: []ChainStep{step("first", failure), step("second", info)}},
+			expectedResults: Results{tagged("failed", Warning, "first"), tagged("ok", Info, "second")},
+		},
+		{
+			name: "stop on failure",
+			chain: StepChain{
+				Steps:         []ChainStep{step("first", failure), step("second", info)},
+				StopOnFailure: true,
+			},
+			expectedResults: Results{tagged("failed", Warning, "first")},
+		},
+		{
+			name:            "stop on Results error",
+			chain:           StepChain{Steps: []ChainStep{step("first", results), step("second", info)}},
+			expectedErr:     "[error]: invalid",
+			expectedResults: nil,
+		},
+		{
+			name:            "stop on error",
+			chain:           StepChain{Steps: []ChainStep{step("first", info), step("second", plainErr), step("third", info)}},
+			expectedErr:     `step "second": broken`,
+			expectedResults: Results{tagged("ok", Info, "first")},
+		},
+		{
+			name: "continue on error",
+			chain: StepChain{
+				Steps:       []ChainStep{step("first", results), step("second", plainErr), step("third", info)},
+				ErrorPolicy: ContinueOnError,
+			},
+			expectedResults: Results{
+				tagged("invalid", Error, "first"),
+				tagged(`step "second": broken`, Error, "second"),
+				tagged("ok", Info, "third"),
+			},
+		},
+		{
+			name: "skip",
+			chain: StepChain{Steps: []ChainStep{
+				{Name: "first", Processor: failure, Skip: func(rl *ResourceList) bool { return len(rl.Items) == 0 }},
+				step("second", info),
+			}},
+			expectedSuccess: true,
+			expectedResults: Results{tagged(`step "first" is skipped`, Info, "first"), tagged("ok", Info, "second")},
+		},
+		{
+			name: "nested chain",
+			chain: StepChain{Steps: []ChainStep{
+				step("outer", StepChain{Steps: []ChainStep{step("inner", info)}}),
+			}},
+			expectedSuccess: true,
+			expectedResults: Results{tagged("ok", Info, "inner")},
+		},
+		{
+			name:        "unknown error policy",
+			chain:       StepChain{Steps: []ChainStep{step("first", info)}, ErrorPolicy: "retry"},
+			expectedErr: `unknown error policy "retry", expect "stop" or "continue"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rl := &ResourceList{}
+			success, err := tc.chain.Process(rl)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedSuccess, success)
+			assert.Equal(t, tc.expectedResults, rl.Results)
+		})
+	}
+}
+
+func TestStepChainCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rl := &ResourceList{}
+	rl.SetContext(ctx)
+	second := false
+	chain := StepChain{
+		Steps: []ChainStep{
+			{Name: "cancel", Processor: ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				cancel()
+				return true, nil
+			})},
+			{Name: "second", Processor: ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				second = true
+				return true, nil
+			})},
+		},
+		ErrorPolicy: ContinueOnError,
+	}
+	success, err := chain.Process(rl)
+	assert.False(t, success)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, second)
+}
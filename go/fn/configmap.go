@@ -0,0 +1,258 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	stringMapType = reflect.TypeOf(map[string]string{})
+)
+
+// assignCMDataToFn decodes the ConfigMap `.data` to the fields of the runner struct. Each `.data` key is matched to
+// a field by its json tag, then by its yaml tag, and then by the field name (case-insensitively). The string value is
+// converted to the field type:
+//   - string, bool, int, uint and float kinds are parsed by the strconv package.
+//   - time.Duration is parsed by time.ParseDuration, e.g. "1m30s".
+//   - slices accept either a comma-separated list (e.g. "a, b, c") or a YAML list (e.g. "[a, b, c]").
+//   - maps, structs and pointers to them accept a YAML object.
+//
+// All the keys, including those matching a field, are also copied to the first field of type map[string]string, if
+// the runner has one and no key is assigned to it. Otherwise, the keys that do not match any field are reported as
+// errors.
+func assignCMDataToFn(runner any, data map[string]string) error {
+	obj := reflect.ValueOf(runner)
+	if obj.Kind() != reflect.Ptr || obj.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the ConfigMap is not of a struct, got %v", obj.Kind().String())
+	}
+	obj = obj.Elem()
+	fields, catchAll := cmDataFields(obj)
+
+	var results Results
+	var unknown []string
+	catchAllAssigned := false
+	for _, key := range sortedKeys(data) {
+		field, found := lookupCMDataField(fields, key)
+		if !found {
+			unknown = append(unknown, key)
+			continue
+		}
+		if catchAll.IsValid() && field.UnsafeAddr() == catchAll.UnsafeAddr() {
+			catchAllAssigned = true
+		}
+		if err := setFromString(field, data[key]); err != nil {
+			results = append(results, cmDataResult(key,
+				fmt.Sprintf("unable to assign ConfigMap key %q to FunctionConfig %v: %v", key, asFnName(runner), err)))
+		}
+	}
+	switch {
+	case catchAll.IsValid() && !catchAllAssigned:
+		// As before the typed fields, the map receives the whole `.data`, including the keys of the typed fields.
+		if catchAll.IsNil() {
+			catchAll.Set(reflect.MakeMap(stringMapType))
+		}
+		for k, v := range data {
+			catchAll.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+		}
+	case !catchAll.IsValid():
+		for _, key := range unknown {
+			results = append(results, cmDataResult(key,
+				fmt.Sprintf("FunctionConfig %v has no field matching ConfigMap key %q", asFnName(runner), key)))
+		}
+	}
+	if len(results) > 0 {
+		return results
+	}
+	return nil
+}
+
+func cmDataResult(key, msg string) *Result {
+	return &Result{
+		Message:  msg,
+		Severity: Error,
+		Field:    &Field{Path: "data." + key},
+	}
+}
+
+// cmDataField is a struct field that can be assigned from a ConfigMap `.data` key.
+type cmDataField struct {
+	key   string
+	value reflect.Value
+}
+
+// cmDataFields lists the settable fields of the struct, including the ones promoted from embedded structs, and the
+// first field of type map[string]string.
+func cmDataFields(obj reflect.Value) ([]cmDataField, reflect.Value) {
+	var fields []cmDataField
+	var catchAll reflect.Value
+	for i := 0; i < obj.NumField(); i++ {
+		sf := obj.Type().Field(i)
+		fv := obj.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+		key, hasTag := fieldKey(sf)
+		if key == "-" {
+			continue
+		}
+		if sf.Anonymous && !hasTag && fv.Kind() == reflect.Struct {
+			embedded, embeddedCatchAll := cmDataFields(fv)
+			fields = append(fields, embedded...)
+			if !catchAll.IsValid() {
+				catchAll = embeddedCatchAll
+			}
+			continue
+		}
+		if !catchAll.IsValid() && fv.Type() == stringMapType {
+			catchAll = fv
+		}
+		fields = append(fields, cmDataField{key: key, value: fv})
+	}
+	return fields, catchAll
+}
+
+// fieldKey returns the name of the struct field in the serialized form, and whether it comes from a json or yaml tag.
+func fieldKey(sf reflect.StructField) (string, bool) {
+	for _, tagKey := range []string{"json", "yaml"} {
+		tag, found := sf.Tag.Lookup(tagKey)
+		if !found {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" {
+			return name, true
+		}
+	}
+	return sf.Name, false
+}
+
+// lookupCMDataField prefers an exact key match, and falls back to a case-insensitive match as encoding/json does.
+func lookupCMDataField(fields []cmDataField, key string) (reflect.Value, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.key, key) {
+			return f.value, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setFromString converts the string value s to the type of v and assigns it to v.
+func setFromString(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setFromString(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		trimmed := strings.TrimSpace(s)
+		if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "- ") || strings.Contains(trimmed, "\n") {
+			return setFromYAML(v, s)
+		}
+		slice := reflect.MakeSlice(v.Type(), 0, 0)
+		if trimmed != "" {
+			for _, elem := range strings.Split(trimmed, ",") {
+				ev := reflect.New(v.Type().Elem()).Elem()
+				if err := setFromString(ev, strings.TrimSpace(elem)); err != nil {
+					return err
+				}
+				slice = reflect.Append(slice, ev)
+			}
+		}
+		v.Set(slice)
+	case reflect.Map, reflect.Struct, reflect.Interface:
+		return setFromYAML(v, s)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+	return nil
+}
+
+// setFromYAML decodes the YAML string s to v. Same as KubeObject.As, it relies on the json tags.
+func setFromYAML(v reflect.Value, s string) error {
+	rn, err := yaml.Parse(s)
+	if err != nil {
+		return err
+	}
+	j, err := rn.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	ptr := reflect.New(v.Type())
+	if err = json.Unmarshal(j, ptr.Interface()); err != nil {
+		return err
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
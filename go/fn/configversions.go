@@ -0,0 +1,84 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VersionedConfig can be implemented by a Runner (or a Generator) to accept multiple functionConfig
+// (apiVersion, kind) pairs, e.g. to evolve the functionConfig from v1alpha1 to v1beta1, or to keep accepting a
+// legacy kind name. If a Runner implements VersionedConfig, only the listed GroupVersionKinds (and ConfigMap) are
+// accepted as its functionConfig.
+type VersionedConfig interface {
+	ConfigVersions() []ConfigVersion
+}
+
+// ConfigVersion is a functionConfig GroupVersionKind accepted by a Runner.
+type ConfigVersion struct {
+	// GroupVersionKind is the GroupVersionKind of the functionConfig.
+	GroupVersionKind schema.GroupVersionKind
+	// Convert assigns the functionConfig of this GroupVersionKind to the Runner. If nil, the functionConfig is
+	// decoded directly into the Runner by KubeObject.As. See ConvertFrom.
+	Convert func(functionConfig *KubeObject) error
+}
+
+// ConvertFrom returns a ConfigVersion.Convert function that decodes the functionConfig to a T, and then calls convert
+// to assign the T value to the Runner.
+// e.g.
+//
+//	func (r *SetLabels) ConfigVersions() []fn.ConfigVersion {
+//		return []fn.ConfigVersion{
+//			{GroupVersionKind: v1beta1GVK},
+//			{GroupVersionKind: v1alpha1GVK, Convert: fn.ConvertFrom(func(in *SetLabelsV1alpha1) error {
+//				r.Labels = in.Labels
+//				return nil
+//			})},
+//		}
+//	}
+func ConvertFrom[T any](convert func(in *T) error) func(functionConfig *KubeObject) error {
+	return func(functionConfig *KubeObject) error {
+		in := new(T)
+		if err := functionConfig.As(in); err != nil {
+			return err
+		}
+		return convert(in)
+	}
+}
+
+// decodeConfigVersion assigns the functionConfig to the runner through the ConfigVersion of the same GroupVersionKind.
+func decodeConfigVersion(runner any, versions []ConfigVersion, o *KubeObject) error {
+	gvk := o.GroupVersionKind()
+	for _, version := range versions {
+		if version.GroupVersionKind != gvk {
+			continue
+		}
+		if version.Convert == nil {
+			return o.As(runner)
+		}
+		return version.Convert(o)
+	}
+	var accepted []string
+	for _, version := range versions {
+		apiVersion, kind := version.GroupVersionKind.ToAPIVersionAndKind()
+		accepted = append(accepted, fmt.Sprintf("`%v/%v`", apiVersion, kind))
+	}
+	accepted = append(accepted, "`v1/ConfigMap`")
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return fmt.Errorf("unsupported FunctionConfig `%v/%v`, expect one of %v", apiVersion, kind, strings.Join(accepted, ", "))
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ VersionedConfig = &SetReplicas{}
+
+// SetReplicas is the internal type. v1beta1 has the same schema, v1alpha1 used the legacy kind `Scale` and
+// a string `count` field.
+type SetReplicas struct {
+	Replicas int `json:"replicas,omitempty"`
+}
+
+type ScaleV1alpha1 struct {
+	Count string `json:"count,omitempty"`
+}
+
+func (r *SetReplicas) ConfigVersions() []ConfigVersion {
+	return []ConfigVersion{
+		{GroupVersionKind: schema.GroupVersionKind{Group: KptFunctionGroup, Version: "v1beta1", Kind: "SetReplicas"}},
+		{
+			GroupVersionKind: schema.GroupVersionKind{Group: KptFunctionGroup, Version: "v1alpha1", Kind: "Scale"},
+			Convert: ConvertFrom(func(in *ScaleV1alpha1) error {
+				replicas, err := strconv.Atoi(in.Count)
+				r.Replicas = replicas
+				return err
+			}),
+		},
+	}
+}
+
+func (*SetReplicas) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	return true
+}
+
+func TestConfigVersions(t *testing.T) {
+	testdata := map[string]struct {
+		fnConfig    string
+		expected    int
+		expectedErr string
+	}{
+		"current version is decoded directly": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1beta1
+kind: SetReplicas
+metadata:
+  name: test
+replicas: 3
+`,
+			expected: 3,
+		},
+		"legacy kind is converted": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: Scale
+metadata:
+  name: test
+count: "2"
+`,
+			expected: 2,
+		},
+		"ConfigMap is still accepted": {
+			fnConfig: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  replicas: "4"
+`,
+			expected: 4,
+		},
+		"unsupported version": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: SetReplicas
+metadata:
+  name: test
+replicas: 3
+`,
+			expectedErr: "[error]: unsupported FunctionConfig `fn.kpt.dev/v1alpha1/SetReplicas`, expect one of " +
+				"`fn.kpt.dev/v1beta1/SetReplicas`, `fn.kpt.dev/v1alpha1/Scale`, `v1/ConfigMap`",
+		},
+	}
+	for description, test := range testdata {
+		runner := &SetReplicas{}
+		fnConfig, err := ParseKubeObject([]byte(test.fnConfig))
+		assert.NoError(t, err, description)
+		rl := &ResourceList{FunctionConfig: fnConfig}
+		ok, err := WithContext(context.TODO(), runner).Process(rl)
+		assert.NoError(t, err, description)
+		if test.expectedErr != "" {
+			assert.False(t, ok, description)
+			assert.Equal(t, test.expectedErr, rl.Results.String(), description)
+			continue
+		}
+		assert.True(t, ok, description)
+		assert.Equal(t, test.expected, runner.Replicas, description)
+	}
+}
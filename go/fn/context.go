@@ -16,6 +16,12 @@ package fn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 var _ context.Context = &Context{}
@@ -23,4 +29,110 @@ var _ context.Context = &Context{}
 // TODO: Have Context implement `context.Context`.
 type Context struct {
 	context.Context
+	// rl is the ResourceList of the Runner, for the Logger.
+	rl *ResourceList
+}
+
+// cancelGracePeriod is how long a cancelled evaluation is waited for to stop. It is a variable for the tests.
+var cancelGracePeriod = 5 * time.Second
+
+// runnerContext is the Context of a Runner (or Generator). It takes the deadline and cancellation from the
+// ResourceList context, and the values from both the ResourceList context and the WithContext (or WithGenerator)
+// context.
+type runnerContext struct {
+	context.Context
+	values context.Context
+}
+
+func newRunnerContext(processorCtx context.Context, rl *ResourceList) context.Context {
+	if processorCtx == nil {
+		return rl.Context()
+	}
+	if rl.ctx == nil {
+		return processorCtx
+	}
+	return runnerContext{Context: rl.ctx, values: processorCtx}
+}
+
+func (c runnerContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.values.Value(key)
+}
+
+const (
+	// TimeoutFlag sets the time limit of the AsMain evaluation, as a duration such as `30s` or `2m`.
+	TimeoutFlag = "--timeout"
+	// TimeoutEnv is the environment variable of the AsMain time limit, used when the TimeoutFlag is not given.
+	TimeoutEnv = "KRM_FN_TIMEOUT"
+)
+
+// newMainContext returns the context of the AsMain evaluation. It is cancelled on SIGINT and SIGTERM, and after the
+// timeout of the TimeoutFlag or the TimeoutEnv, if any. The cause of the cancellation wraps context.Canceled or
+// context.DeadlineExceeded. Only the first signal is caught: a second one kills the function as usual.
+func newMainContext(args []string) (context.Context, context.CancelFunc, error) {
+	timeout, err := lookupTimeout(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, cancelCause := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			cancelCause(fmt.Errorf("function interrupted by signal %v: %w", sig, context.Canceled))
+		case <-base.Done():
+		}
+	}()
+	cancel := func() {
+		signal.Stop(signals)
+		cancelCause(nil)
+	}
+	if timeout <= 0 {
+		return base, cancel, nil
+	}
+	ctx, cancelTimeout := context.WithTimeoutCause(base, timeout,
+		fmt.Errorf("function timed out after %v: %w", timeout, context.DeadlineExceeded))
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}, nil
+}
+
+// lookupTimeout returns the timeout of the TimeoutFlag or the TimeoutEnv, or 0 if none is given.
+func lookupTimeout(args []string) (time.Duration, error) {
+	value, found := lookupArg(args, TimeoutFlag)
+	source := TimeoutFlag
+	if !found {
+		value, found = os.LookupEnv(TimeoutEnv)
+		source = TimeoutEnv
+	}
+	if !found || value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v %q: %w", source, value, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid %v %q: the timeout must not be negative", source, value)
+	}
+	return timeout, nil
+}
+
+// contextError returns the cause of the ResourceList context if it is done, or nil.
+func contextError(rl *ResourceList) error {
+	ctx := rl.Context()
+	if ctx.Err() == nil {
+		return nil
+	}
+	return context.Cause(ctx)
+}
+
+// isContextError tells whether err stops the function because its context is done.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
@@ -0,0 +1,238 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupTimeout(t *testing.T) {
+	testcases := []struct {
+		name          string
+		args          []string
+		env           string
+		expected      time.Duration
+		expectedError string
+	}{
+		{name: "no timeout"},
+		{name: "flag", args: []string{"--timeout=30s"}, expected: 30 * time.Second},
+		{name: "separate flag value", args: []string{"--timeout", "2m"}, expected: 2 * time.Minute},
+		{name: "env", env: "1m30s", expected: 90 * time.Second},
+		{name: "flag over env", args: []string{"--timeout=5s"}, env: "1m", expected: 5 * time.Second},
+		{name: "go test flag is ignored", args: []string{"-test.timeout=10m0s"}},
+		{
+			name:          "invalid",
+			env:           "soon",
+			expectedError: `invalid KRM_FN_TIMEOUT "soon": time: invalid duration "soon"`,
+		},
+		{
+			name:          "negative",
+			args:          []string{"--timeout=-1s"},
+			expectedError: `invalid --timeout "-1s": the timeout must not be negative`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(TimeoutEnv, tc.env)
+			timeout, err := lookupTimeout(tc.args)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, timeout)
+		})
+	}
+}
+
+func TestMainContext(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel, err := newMainContext([]string{"--timeout=10ms"})
+		assert.NoError(t, err)
+		defer cancel()
+		<-ctx.Done()
+		cause := context.Cause(ctx)
+		assert.EqualError(t, cause, "function timed out after 10ms: context deadline exceeded")
+		assert.True(t, errors.Is(cause, context.DeadlineExceeded))
+	})
+	t.Run("signal", func(t *testing.T) {
+		ctx, cancel, err := newMainContext(nil)
+		assert.NoError(t, err)
+		defer cancel()
+		assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("the context is not cancelled by SIGTERM")
+		}
+		cause := context.Cause(ctx)
+		assert.EqualError(t, cause, "function interrupted by signal terminated: context canceled")
+		assert.True(t, errors.Is(cause, context.Canceled))
+	})
+}
+
+func TestCancelledEvaluation(t *testing.T) {
+	cancelled := fmt.Errorf("function interrupted: %w", context.Canceled)
+	testcases := []struct {
+		name      string
+		processor func(cancel context.CancelFunc) ResourceListProcessor
+		// expectedApplied is the number of processors or items applied before the cancellation stops the evaluation.
+		expectedApplied int
+	}{
+		{
+			name: "chain",
+			processor: func(cancel context.CancelFunc) ResourceListProcessor {
+				return Chain(
+					ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+						rl.Results.Infof("applied")
+						cancel()
+						return true, nil
+					}),
+					ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+						rl.Results.Infof("applied")
+						return true, nil
+					}),
+				)
+			},
+			expectedApplied: 1,
+		},
+		{
+			name: "selector",
+			processor: func(cancel context.CancelFunc) ResourceListProcessor {
+				return ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+					rl.Items = append(rl.Items, rl.Items[0].Copy())
+					return true, ApplyFnBySelector(rl, func(*KubeObject) bool { return true }, func(*KubeObject) error {
+						rl.Results.Infof("applied")
+						cancel()
+						return nil
+					})
+				})
+			},
+			expectedApplied: 1,
+		},
+		{
+			name: "runner",
+			processor: func(cancel context.CancelFunc) ResourceListProcessor {
+				return WithContext(context.Background(), runnerFunc(func(ctx *Context, results *Results) bool {
+					cancel()
+					<-ctx.Done()
+					return true
+				}))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			var out bytes.Buffer
+			err := ExecuteContext(ctx, tc.processor(func() { cancel(cancelled) }), strings.NewReader(runInput), &out)
+			assert.Equal(t, cancelled, err)
+
+			rl, parseErr := ParseResourceList(out.Bytes())
+			assert.NoError(t, parseErr)
+			var applied int
+			for _, result := range rl.Results {
+				if result.Message == "applied" {
+					applied++
+				}
+			}
+			assert.Equal(t, tc.expectedApplied, applied)
+			last := rl.Results[len(rl.Results)-1]
+			assert.Equal(t, cancelled.Error(), last.Message)
+			assert.Equal(t, Error, last.Severity)
+		})
+	}
+}
+
+func TestAbandonedEvaluation(t *testing.T) {
+	gracePeriod := cancelGracePeriod
+	cancelGracePeriod = 10 * time.Millisecond
+	defer func() { cancelGracePeriod = gracePeriod }()
+	release := make(chan struct{})
+	defer close(release)
+	// The Runner ignores the cancellation, and modifies the items until it is released.
+	p := WithContext(context.Background(), runnerFunc(func(ctx *Context, results *Results) bool {
+		ctx.rl.Items[0].SetName("modified")
+		<-release
+		return true
+	}))
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Millisecond,
+		fmt.Errorf("function timed out after 10ms: %w", context.DeadlineExceeded))
+	defer cancel()
+	var out bytes.Buffer
+	err := execute(ctx, p, newByteReadWriter(strings.NewReader(runInput), &out), &options{abandonCancelled: true})
+	assert.EqualError(t, err,
+		"the function did not stop within 10ms: function timed out after 10ms: context deadline exceeded")
+
+	expected, parseErr := ParseResourceList([]byte(runInput))
+	assert.NoError(t, parseErr)
+	rl, parseErr := ParseResourceList(out.Bytes())
+	assert.NoError(t, parseErr)
+	assert.Equal(t, expected.Items[0].String(), rl.Items[0].String(), "the input items are written")
+	last := rl.Results[len(rl.Results)-1]
+	assert.Equal(t, err.Error(), last.Message)
+	assert.Equal(t, Error, last.Severity)
+}
+
+func TestAbandonedLocalEvaluation(t *testing.T) {
+	gracePeriod := cancelGracePeriod
+	cancelGracePeriod = 10 * time.Millisecond
+	defer func() { cancelGracePeriod = gracePeriod }()
+	release := make(chan struct{})
+	defer close(release)
+	// The processor ignores the cancellation, and deletes the items until it is released.
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Items = nil
+		<-release
+		return true, nil
+	})
+	dir := t.TempDir()
+	content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "cm.yaml"), []byte(content), 0644))
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("interrupted"))
+	var out bytes.Buffer
+	rw := newLocalReadWriter(localFlags{inputDir: dir, outputDir: dir}, strings.NewReader(""), &out)
+	err := execute(ctx, p, rw, &options{abandonCancelled: true})
+	assert.EqualError(t, err, "the function did not stop within 10ms: interrupted")
+	assert.Empty(t, out.String())
+	actual, readErr := os.ReadFile(filepath.Join(dir, "cm.yaml"))
+	assert.NoError(t, readErr, "the input files are not removed")
+	assert.Equal(t, content, string(actual))
+}
+
+// runnerFunc is a Runner of a function.
+type runnerFunc func(ctx *Context, results *Results) bool
+
+func (f runnerFunc) Run(ctx *Context, _ *KubeObject, _ KubeObjects, results *Results) bool {
+	return f(ctx, results)
+}
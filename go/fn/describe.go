@@ -0,0 +1,234 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// DescribeFlag makes AsMain print the FunctionMetadata to STDOUT instead of evaluating the ResourceList.
+	DescribeFlag = "--describe"
+	// FunctionMetadataKind is the kind of the document printed by DescribeFlag.
+	FunctionMetadataKind = "FunctionMetadata"
+)
+
+// Describer can be implemented by a Runner, a Generator or a ResourceListProcessor to complete the FunctionMetadata
+// that the SDK derives from it, e.g. to give the function description or an example functionConfig.
+type Describer interface {
+	Describe(md *FunctionMetadata)
+}
+
+// FunctionMetadata is the machine-readable description of a function, e.g. for a function catalog.
+type FunctionMetadata struct {
+	// Name is the function name. It defaults to the Runner struct name in kebab case (e.g. `set-labels`), or to
+	// the name of the function binary.
+	Name string `yaml:"-"`
+	// Description is a short description of what the function does.
+	Description string `yaml:"description,omitempty"`
+	// Mutates tells whether the function modifies, adds or deletes resources. It defaults to true.
+	Mutates bool `yaml:"mutates"`
+	// Validates tells whether the function reports on the resources. It defaults to false.
+	Validates bool `yaml:"validates"`
+	// ConfigTypes are the accepted functionConfig types.
+	ConfigTypes []ConfigType `yaml:"configTypes,omitempty"`
+	// ConfigSchema is the OpenAPI v3 schema of the functionConfig. See ConfigSchema.
+	ConfigSchema *JSONSchemaProps `yaml:"configSchema,omitempty"`
+	// ExampleConfig is an example functionConfig. It defaults to the functionConfig with the DefaultTag values.
+	ExampleConfig *KubeObject `yaml:"-"`
+}
+
+// ConfigType is the apiVersion and kind of a functionConfig.
+type ConfigType struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+// NewFunctionMetadata returns the FunctionMetadata of the AsMain input. The config types, schema and example
+// functionConfig are derived from the Runner (or Generator) struct, and the Describer, if implemented, completes
+// the rest.
+func NewFunctionMetadata(input any) (*FunctionMetadata, error) {
+	md := &FunctionMetadata{
+		Name:    filepath.Base(os.Args[0]),
+		Mutates: true,
+	}
+	runner := input
+	if rp, ok := unwrapRunner(input); ok {
+		runner = rp.fnRunner
+	}
+	switch runner.(type) {
+	case Runner, Generator:
+		if err := describeRunner(runner, md); err != nil {
+			return nil, err
+		}
+	}
+	if describer, ok := runner.(Describer); ok {
+		describer.Describe(md)
+	} else if describer, ok := input.(Describer); ok {
+		describer.Describe(md)
+	}
+	return md, nil
+}
+
+// functionName returns the FunctionMetadata name of the AsMain input.
+func functionName(input any) string {
+	if md, err := NewFunctionMetadata(input); err == nil {
+		return md.Name
+	}
+	return filepath.Base(os.Args[0])
+}
+
+func describeRunner(runner any, md *FunctionMetadata) error {
+	kind := asFnName(runner)
+	if kind == "" {
+		return nil
+	}
+	md.Name = kebabCase(kind)
+	example := ConfigType{APIVersion: KptFunctionAPIVersion, Kind: kind}
+	if versioned, ok := runner.(VersionedConfig); ok {
+		// Prefer the first version that is decoded directly into the Runner as the example.
+		direct := false
+		for i, version := range versioned.ConfigVersions() {
+			apiVersion, kind := version.GroupVersionKind.ToAPIVersionAndKind()
+			md.ConfigTypes = append(md.ConfigTypes, ConfigType{APIVersion: apiVersion, Kind: kind})
+			if i == 0 || (!direct && version.Convert == nil) {
+				example = md.ConfigTypes[i]
+				direct = version.Convert == nil
+			}
+		}
+	} else {
+		md.ConfigTypes = append(md.ConfigTypes, example)
+	}
+	md.ConfigTypes = append(md.ConfigTypes, ConfigType{APIVersion: "v1", Kind: "ConfigMap"})
+
+	s, err := ConfigSchema(runner, nil)
+	if err != nil {
+		return err
+	}
+	md.ConfigSchema = s
+	md.ExampleConfig, err = exampleConfig(runner, example)
+	return err
+}
+
+// exampleConfig returns a functionConfig of the given type whose fields are the DefaultTag values of the runner.
+func exampleConfig(runner any, configType ConfigType) (*KubeObject, error) {
+	config := reflect.New(reflect.TypeOf(runner).Elem())
+	// The required fields are left empty, so the validation errors are expected.
+	_ = defaultAndValidate(config.Interface(), "", nil)
+	// Go through json, which is what KubeObject.As relies on, but keep the integers as they are.
+	j, err := json.Marshal(config.Interface())
+	if err != nil {
+		return nil, err
+	}
+	rn, err := yaml.Parse(string(j))
+	if err != nil {
+		return nil, err
+	}
+	o := NewEmptyKubeObject()
+	if err = o.SetAPIVersion(configType.APIVersion); err != nil {
+		return nil, err
+	}
+	if err = o.SetKind(configType.Kind); err != nil {
+		return nil, err
+	}
+	if err = o.SetName("example"); err != nil {
+		return nil, err
+	}
+	// Append the fields after the KRM header, and skip the unset ones.
+	node := o.obj.Node()
+	fields := rn.YNode().Content
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1].Tag == yaml.NodeTagNull {
+			continue
+		}
+		clearStyle(fields[i])
+		clearStyle(fields[i+1])
+		node.Content = append(node.Content, fields[i], fields[i+1])
+	}
+	return o, nil
+}
+
+// clearStyle turns the json flow style into the yaml block style. The strings that would otherwise be read as
+// another type are still quoted by the encoder.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// ToYAML returns the FunctionMetadata as a KRM resource of kind FunctionMetadataKind.
+func (md *FunctionMetadata) ToYAML() ([]byte, error) {
+	doc := struct {
+		APIVersion       string `yaml:"apiVersion"`
+		Kind             string `yaml:"kind"`
+		Metadata         struct{ Name string }
+		FunctionMetadata `yaml:",inline"`
+	}{
+		APIVersion:       KptFunctionAPIVersion,
+		Kind:             FunctionMetadataKind,
+		FunctionMetadata: *md,
+	}
+	doc.Metadata.Name = md.Name
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if md.ExampleConfig == nil {
+		return b, nil
+	}
+	rn, err := yaml.Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+	if err = rn.PipeE(yaml.SetField("exampleConfig", yaml.NewRNode(md.ExampleConfig.obj.Node()))); err != nil {
+		return nil, err
+	}
+	s, err := rn.String()
+	return []byte(s), err
+}
+
+// describeRequested tells whether the DescribeFlag is in args. The other arguments are ignored.
+func describeRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == DescribeFlag || arg == strings.TrimPrefix(DescribeFlag, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+func kebabCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Break before an upper case letter that starts a word, e.g. "SetLabels" and "HTTPRoute".
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
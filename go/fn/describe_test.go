@@ -0,0 +1,128 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Describer = &SetAnnotations{}
+
+type SetAnnotations struct {
+	Annotations map[string]string `json:"annotations" kptValidate:"required"`
+	Replicas    int               `json:"replicas" kptDefault:"3"`
+	Mode        string            `json:"mode" kptDefault:"merge" kptValidate:"enum=merge|replace"`
+}
+
+func (r *SetAnnotations) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	return true
+}
+
+func (r *SetAnnotations) Describe(md *FunctionMetadata) {
+	md.Description = "set the annotations of all resources"
+}
+
+func TestAsMainDescribe(t *testing.T) {
+	origArgs := os.Args
+	os.Args = []string{"set-annotations", "--describe"}
+	defer func() { os.Args = origArgs }()
+
+	out := asMainWithStdio(t, &SetAnnotations{}, "")
+	assert.Equal(t, `apiVersion: fn.kpt.dev/v1alpha1
+kind: FunctionMetadata
+metadata:
+  name: set-annotations
+description: set the annotations of all resources
+mutates: true
+validates: false
+configTypes:
+- apiVersion: fn.kpt.dev/v1alpha1
+  kind: SetAnnotations
+- apiVersion: v1
+  kind: ConfigMap
+configSchema:
+  type: object
+  properties:
+    annotations:
+      type: object
+      additionalProperties:
+        type: string
+    apiVersion:
+      type: string
+    kind:
+      type: string
+    metadata:
+      type: object
+    mode:
+      type: string
+      default: merge
+      enum:
+      - merge
+      - replace
+    replicas:
+      type: integer
+      format: int64
+      default: 3
+  required:
+  - annotations
+exampleConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: SetAnnotations
+  metadata:
+    name: example
+  replicas: 3
+  mode: merge
+`, out)
+}
+
+func TestNewFunctionMetadata(t *testing.T) {
+	md, err := NewFunctionMetadata(WithContext(context.Background(), &SetReplicas{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "set-replicas", md.Name)
+	assert.Equal(t, []ConfigType{
+		{APIVersion: "fn.kpt.dev/v1beta1", Kind: "SetReplicas"},
+		{APIVersion: "fn.kpt.dev/v1alpha1", Kind: "Scale"},
+		{APIVersion: "v1", Kind: "ConfigMap"},
+	}, md.ConfigTypes)
+	assert.Equal(t, "fn.kpt.dev/v1beta1", md.ExampleConfig.GetAPIVersion())
+	assert.Equal(t, "SetReplicas", md.ExampleConfig.GetKind())
+
+	// The Runner is found through the middlewares.
+	md, err = NewFunctionMetadata(Use(WithContext(context.Background(), &SetReplicas{}), Recover(), ReadOnly()))
+	assert.NoError(t, err)
+	assert.Equal(t, "set-replicas", md.Name)
+	assert.Equal(t, "SetReplicas", md.ExampleConfig.GetKind())
+
+	md, err = NewFunctionMetadata(&removeAll{})
+	assert.NoError(t, err)
+	assert.Empty(t, md.ConfigTypes)
+	assert.Nil(t, md.ConfigSchema)
+	assert.True(t, md.Mutates)
+}
+
+func TestKebabCase(t *testing.T) {
+	for in, expected := range map[string]string{
+		"SetLabels":      "set-labels",
+		"HTTPRoute":      "http-route",
+		"SetNamespaceV2": "set-namespace-v2",
+		"x":              "x",
+	} {
+		assert.Equal(t, expected, kebabCase(in))
+	}
+}
@@ -43,6 +43,31 @@ A general workflow is:
  4. Writes the modified "ResourceList" to STDOUT.
  5. Write function message to "ResourceList.Results" with severity "Info", "Warning" or "Error"
 
+"Results.ErrorAt", "Results.WarningAt" and "Results.InfoAt" point a result at a field of a SubObject: the resource,
+file, field path and position of the field in the resource ("ResourcePosition", which is not the line in the file)
+are filled from the SubObject.
+
+A "Fixer" applies the "Field.ProposedValue" of the results to the items, so that a validator can also run in
+auto-remediation mode, or report the fixes with "DryRun".
+
+"WithResultPolicy" relaxes or tightens the results at the end of the evaluation: warnings as errors, suppressions by
+tag, message, resource or the "fn.kpt.dev/suppress" annotation, caps per severity and deduplication.
+
+"ResourceList.Logger" (or "Context.Logger" in a Runner) returns a log/slog logger writing to STDERR, at the level of
+the KRM_FN_LOG_LEVEL environment variable (e.g. "debug"). With "WithLogResults", its Warn and Error records are also
+added to the results, with the resource of their "ResourceAttr" attribute.
+
+A "Middleware" wraps a ResourceListProcessor, e.g. with "Logging", "Timing", "Recover", "TagResults", "ReadOnly" or
+"SnapshotInput". "Use" applies the middlewares to a processor, which can be given to "AsMain" or "Execute" as is, and
+"WithMiddleware" applies them to the processor of "AsMain", "Execute" or "Run".
+
+A "StepChain" is a configurable "Chain": it stops or continues on error ("ErrorPolicy"), can stop after the first
+failed step, skips the steps whose "Skip" condition is met, and tags the results of each step with its name
+("fn.kpt.dev/step").
+
+"Scope" makes a processor see only the items matched by a selector, e.g. "IsGroupKind" or "MatchesSelector" of a
+Kptfile selector, and merges its additions, deletions and modifications back into the items.
+
 # KubeObject
 
 The KubeObject is the basic unit to perform operations on KRM resources.
@@ -71,6 +96,40 @@ Besides unstructured style, another way to use KubeObject is to purely work on t
 handles the ResourceList parsing, KRM resource field type detection, read from STDIN and write to STDOUT.
 
 "AsMain" accepts a struct that either implement the ResourceListProcessor interface or Runner interface.
+The ResourceList can be in YAML or JSON format, and the output is written in the same format as the input.
+
+When the function binary is called with "--describe", "AsMain" prints the FunctionMetadata (name, description,
+functionConfig types, schema and example) instead. Implement the Describer interface to complete it.
+
+For local debugging without kpt, "--input-dir", "--output-dir" and "--config" make "AsMain" read the resources from
+and write them to a local directory, and read the functionConfig from a file.
+
+If the function panics, "AsMain" reports the panic as an Error result on the object being processed (when known),
+prints the stack trace to STDERR and returns a PanicError. Use "ExitCode" to exit with a distinct code:
+
+	if err := fn.AsMain(runner); err != nil {
+		os.Exit(fn.ExitCode(err))
+	}
+
+"AsMain" cancels the context of the evaluation on SIGINT and SIGTERM, and after the timeout given by "--timeout" or
+the KRM_FN_TIMEOUT environment variable (e.g. "30s"). "Chain" and "ApplyFnBySelector" then stop, and the
+cancellation is reported as an Error result. Long-running functions should check "ResourceList.Context" (or the
+Runner Context) as well.
+
+With "--sarif=<file>", "AsMain" also writes the results in the SARIF 2.1.0 format, so that the validator findings
+can be uploaded as code scanning annotations. "Results.ToSARIF" does the same conversion as a library, and
+"Results.ToJUnit" converts the results to a JUnit XML report for the CI dashboards.
+
+When STDERR is a terminal, "AsMain" also prints the results there, grouped by file and resource, with the line of
+their field in the resource YAML and colorized severities (unless NO_COLOR is set). "Results.Render" does the same
+formatting as a library.
+
+With the KRM_FN_TRACE_FILE environment variable, "Run", "Execute" and "AsMain" append a JSON trace of the evaluation
+to the file: the spans of "Chain", "ChainFunctions" and "ApplyFnBySelector", and the bytes parsed, items processed and
+results per severity. Nothing is traced when it is unset.
+
+To run a function as a long-lived service rather than a container, "Serve" evaluates the ResourceLists POSTed to an
+HTTP server the same way as "AsMain" does.
 
 See github.com/kptdev/krm-functions-sdk/go/fn/examples for detailed usage.
 */
@@ -0,0 +1,212 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The FunctionEvaluator protocol of Porch (https://github.com/nephio-project/porch/tree/main/func/evaluator).
+// Keep the package, service and message definitions wire-compatible with it.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: evaluator.proto
+
+package evaluator
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EvaluateFunctionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Serialized ResourceList (the function input).
+	ResourceList []byte `protobuf:"bytes,1,opt,name=resource_list,json=resourceList,proto3" json:"resource_list,omitempty"`
+	// Image of the function to evaluate.
+	Image         string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateFunctionRequest) Reset() {
+	*x = EvaluateFunctionRequest{}
+	mi := &file_evaluator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateFunctionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateFunctionRequest) ProtoMessage() {}
+
+func (x *EvaluateFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_evaluator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateFunctionRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_evaluator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EvaluateFunctionRequest) GetResourceList() []byte {
+	if x != nil {
+		return x.ResourceList
+	}
+	return nil
+}
+
+func (x *EvaluateFunctionRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+type EvaluateFunctionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Serialized ResourceList (the function output).
+	ResourceList []byte `protobuf:"bytes,1,opt,name=resource_list,json=resourceList,proto3" json:"resource_list,omitempty"`
+	// Additional log of the function evaluation.
+	Log           []byte `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateFunctionResponse) Reset() {
+	*x = EvaluateFunctionResponse{}
+	mi := &file_evaluator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateFunctionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateFunctionResponse) ProtoMessage() {}
+
+func (x *EvaluateFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_evaluator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateFunctionResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_evaluator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EvaluateFunctionResponse) GetResourceList() []byte {
+	if x != nil {
+		return x.ResourceList
+	}
+	return nil
+}
+
+func (x *EvaluateFunctionResponse) GetLog() []byte {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
+var File_evaluator_proto protoreflect.FileDescriptor
+
+const file_evaluator_proto_rawDesc = "" +
+	"\n" +
+	"\x0fevaluator.proto\x12\tevaluator\"T\n" +
+	"\x17EvaluateFunctionRequest\x12#\n" +
+	"\rresource_list\x18\x01 \x01(\fR\fresourceList\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\"Q\n" +
+	"\x18EvaluateFunctionResponse\x12#\n" +
+	"\rresource_list\x18\x01 \x01(\fR\fresourceList\x12\x10\n" +
+	"\x03log\x18\x02 \x01(\fR\x03log2r\n" +
+	"\x11FunctionEvaluator\x12]\n" +
+	"\x10EvaluateFunction\x12\".evaluator.EvaluateFunctionRequest\x1a#.evaluator.EvaluateFunctionResponse\"\x00B5Z3github.com/kptdev/krm-functions-sdk/go/fn/evaluatorb\x06proto3"
+
+var (
+	file_evaluator_proto_rawDescOnce sync.Once
+	file_evaluator_proto_rawDescData []byte
+)
+
+func file_evaluator_proto_rawDescGZIP() []byte {
+	file_evaluator_proto_rawDescOnce.Do(func() {
+		file_evaluator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_evaluator_proto_rawDesc), len(file_evaluator_proto_rawDesc)))
+	})
+	return file_evaluator_proto_rawDescData
+}
+
+var file_evaluator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_evaluator_proto_goTypes = []any{
+	(*EvaluateFunctionRequest)(nil),  // 0: evaluator.EvaluateFunctionRequest
+	(*EvaluateFunctionResponse)(nil), // 1: evaluator.EvaluateFunctionResponse
+}
+var file_evaluator_proto_depIdxs = []int32{
+	0, // 0: evaluator.FunctionEvaluator.EvaluateFunction:input_type -> evaluator.EvaluateFunctionRequest
+	1, // 1: evaluator.FunctionEvaluator.EvaluateFunction:output_type -> evaluator.EvaluateFunctionResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_evaluator_proto_init() }
+func file_evaluator_proto_init() {
+	if File_evaluator_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_evaluator_proto_rawDesc), len(file_evaluator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_evaluator_proto_goTypes,
+		DependencyIndexes: file_evaluator_proto_depIdxs,
+		MessageInfos:      file_evaluator_proto_msgTypes,
+	}.Build()
+	File_evaluator_proto = out.File
+	file_evaluator_proto_goTypes = nil
+	file_evaluator_proto_depIdxs = nil
+}
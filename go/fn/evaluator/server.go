@@ -0,0 +1,114 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evaluator hosts in-process KRM functions behind the FunctionEvaluator gRPC protocol of Porch, so that Go
+// functions can be evaluated in an evaluator pod without a container per call.
+package evaluator
+
+//go:generate buf generate
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ FunctionEvaluatorServer = &Server{}
+
+// Server evaluates the ResourceLists with the ResourceListProcessor registered for the requested function image.
+type Server struct {
+	UnimplementedFunctionEvaluatorServer
+	functions map[string]fn.ResourceListProcessor
+}
+
+// NewServer returns the Server of the functions keyed by image name. An image is matched by its full name (e.g.
+// `ghcr.io/kptdev/krm-functions-catalog/set-labels:v0.2`), and then by its name without the tag or digest (e.g.
+// `ghcr.io/kptdev/krm-functions-catalog/set-labels`). The functions are evaluated the same way as fn.AsMain does,
+// and concurrently, see fn.Concurrent.
+func NewServer(functions map[string]fn.ResourceListProcessor) *Server {
+	s := &Server{functions: map[string]fn.ResourceListProcessor{}}
+	for image, p := range functions {
+		s.functions[image] = fn.Concurrent(p)
+	}
+	return s
+}
+
+// ListenAndServe serves the functions on the TCP network address addr. See NewServer.
+func ListenAndServe(addr string, functions map[string]fn.ResourceListProcessor) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer()
+	RegisterFunctionEvaluatorServer(s, NewServer(functions))
+	return s.Serve(lis)
+}
+
+// EvaluateFunction evaluates the request ResourceList with the function of the request image. The response log holds
+// the results of the function. If the function fails, the error has code Internal, and its message holds the
+// results.
+func (s *Server) EvaluateFunction(ctx context.Context, req *EvaluateFunctionRequest) (*EvaluateFunctionResponse, error) {
+	p, found := s.lookup(req.GetImage())
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no function is registered for image %q", req.GetImage())
+	}
+	var out bytes.Buffer
+	err := fn.ExecuteContext(ctx, p, bytes.NewReader(req.GetResourceList()), &out)
+	if err != nil && out.Len() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to evaluate function %q: %v", req.GetImage(), err)
+	}
+	log := resultsLog(out.Bytes())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to evaluate function %q: %v\n%s", req.GetImage(), err, log)
+	}
+	return &EvaluateFunctionResponse{ResourceList: out.Bytes(), Log: log}, nil
+}
+
+func (s *Server) lookup(image string) (fn.ResourceListProcessor, bool) {
+	if p, found := s.functions[image]; found {
+		return p, true
+	}
+	p, found := s.functions[imageName(image)]
+	return p, found
+}
+
+// imageName trims the tag and the digest of the image.
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	// The last colon is a tag separator if it is after the last slash, otherwise it is a registry port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// resultsLog formats the results of the output ResourceList, one per line.
+func resultsLog(out []byte) []byte {
+	rl, err := fn.ParseResourceList(out)
+	if err != nil {
+		return nil
+	}
+	var log bytes.Buffer
+	for _, result := range rl.Results {
+		log.WriteString(result.String())
+		log.WriteString("\n")
+	}
+	return log.Bytes()
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// SetTeam sets the `team` label to all resources.
+type SetTeam struct {
+	Team string `json:"team" kptValidate:"required"`
+}
+
+func (r *SetTeam) Run(_ *fn.Context, _ *fn.KubeObject, items fn.KubeObjects, results *fn.Results) bool {
+	for _, item := range items {
+		if err := item.SetLabel("team", r.Team); err != nil {
+			results.ErrorE(err)
+			return false
+		}
+	}
+	results.Infof("set team %v", r.Team)
+	return true
+}
+
+const input = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+functionConfig:
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: fn-config
+  data:
+    team: %v
+`
+
+// newTestClient serves the functions in memory, and returns a client of the server.
+func newTestClient(t *testing.T, functions map[string]fn.ResourceListProcessor) FunctionEvaluatorClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterFunctionEvaluatorServer(s, NewServer(functions))
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewFunctionEvaluatorClient(conn)
+}
+
+func TestEvaluateFunction(t *testing.T) {
+	client := newTestClient(t, map[string]fn.ResourceListProcessor{
+		"example.com/set-team": fn.WithContext(context.Background(), &SetTeam{}),
+	})
+
+	testcases := map[string]struct {
+		image          string
+		resourceList   string
+		expectedOutput string
+		expectedLog    string
+		expectedCode   codes.Code
+	}{
+		"image with tag": {
+			image:        "example.com/set-team:v1",
+			resourceList: fmt.Sprintf(input, "sre"),
+			expectedOutput: `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+    labels:
+      team: sre
+functionConfig:
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: fn-config
+  data:
+    team: sre
+results:
+- message: set team sre
+  severity: info
+`,
+			expectedLog:  "[info]: set team sre\n",
+			expectedCode: codes.OK,
+		},
+		"function failure": {
+			image:        "example.com/set-team",
+			resourceList: fmt.Sprintf(input, `""`),
+			expectedCode: codes.Internal,
+		},
+		"unknown image": {
+			image:        "example.com/unknown:v1",
+			resourceList: fmt.Sprintf(input, "sre"),
+			expectedCode: codes.NotFound,
+		},
+		"invalid ResourceList": {
+			image:        "example.com/set-team",
+			resourceList: "- not a ResourceList",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for name, test := range testcases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.EvaluateFunction(context.Background(), &EvaluateFunctionRequest{
+				ResourceList: []byte(test.resourceList),
+				Image:        test.image,
+			})
+			assert.Equal(t, test.expectedCode, status.Code(err), err)
+			if test.expectedCode == codes.OK {
+				assert.Equal(t, test.expectedOutput, string(resp.GetResourceList()))
+				assert.Equal(t, test.expectedLog, string(resp.GetLog()))
+			}
+		})
+	}
+}
+
+// SetLabels sets the labels of its map, which the caller initializes, and of the ConfigMap functionConfig.
+type SetLabels struct {
+	Labels map[string]string
+}
+
+func (r *SetLabels) Run(_ *fn.Context, _ *fn.KubeObject, items fn.KubeObjects, results *fn.Results) bool {
+	for _, item := range items {
+		for key, value := range r.Labels {
+			if err := item.SetLabel(key, value); err != nil {
+				results.ErrorE(err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEvaluateFunctionConcurrently(t *testing.T) {
+	runner := &SetLabels{Labels: map[string]string{"owner": "platform"}}
+	client := newTestClient(t, map[string]fn.ResourceListProcessor{
+		"example.com/set-labels": fn.WithContext(context.Background(), runner),
+	})
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			team := fmt.Sprintf("team-%d", i)
+			resp, err := client.EvaluateFunction(context.Background(), &EvaluateFunctionRequest{
+				ResourceList: []byte(fmt.Sprintf(input, team)),
+				Image:        "example.com/set-labels",
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			rl, err := fn.ParseResourceList(resp.GetResourceList())
+			assert.NoError(t, err)
+			// Each evaluation sees the initial labels and its own functionConfig only.
+			assert.Equal(t, map[string]string{"owner": "platform", "team": team}, rl.Items[0].GetLabels())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]string{"owner": "platform"}, runner.Labels)
+}
+
+func TestImageName(t *testing.T) {
+	for image, expected := range map[string]string{
+		"set-labels":                             "set-labels",
+		"ghcr.io/kptdev/set-labels:v0.2":         "ghcr.io/kptdev/set-labels",
+		"localhost:5000/set-labels":              "localhost:5000/set-labels",
+		"localhost:5000/set-labels:v1@sha256:ab": "localhost:5000/set-labels",
+	} {
+		assert.Equal(t, expected, imageName(image))
+	}
+}
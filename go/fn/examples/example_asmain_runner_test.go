@@ -71,7 +71,7 @@ func Example_asMain() {
 	os.Stdin = file
 	ctx := context.TODO()
 	if err := fn.AsMain(fn.WithContext(ctx, &SetLabels{})); err != nil {
-		os.Exit(1)
+		os.Exit(fn.ExitCode(err))
 	}
 	// Output:
 	// apiVersion: config.kubernetes.io/v1
@@ -0,0 +1,99 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn"
+)
+
+// runAsNonRoot requires the workloads to set `spec.template.spec.securityContext.runAsNonRoot`.
+func runAsNonRoot(rl *fn.ResourceList) (bool, error) {
+	valid := true
+	for _, obj := range rl.Items.Where(hasDesiredGVK) {
+		runAsNonRoot, _, err := obj.NestedBool("spec", "template", "spec", "securityContext", "runAsNonRoot")
+		if err != nil {
+			return false, err
+		}
+		if !runAsNonRoot {
+			rl.Results = append(rl.Results, fn.ConfigObjectResult("runAsNonRoot must be true", obj, fn.Error))
+			valid = false
+		}
+	}
+	return valid, nil
+}
+
+// This example wraps a validator with middlewares: the validator is logged, recovered from its panics, not allowed
+// to mutate the resources, and its results are tagged. A function would give the processor to fn.AsMain instead.
+func Example_middleware() {
+	p := fn.Use(fn.ResourceListProcessorFunc(runAsNonRoot),
+		fn.Logging("validator"),
+		fn.Recover(),
+		fn.ReadOnly(),
+		fn.TagResults(map[string]string{"validator": "run-as-non-root"}),
+	)
+	reader := strings.NewReader(`
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+  spec:
+    template:
+      spec:
+        containers:
+        - name: nginx
+          image: nginx:1.29`)
+
+	var writer bytes.Buffer
+	err := fn.Execute(p, reader, &writer)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	fmt.Println(writer.String())
+
+	// Output:
+	// error: function failure
+	// apiVersion: config.kubernetes.io/v1
+	// kind: ResourceList
+	// items:
+	// - apiVersion: apps/v1
+	//   kind: Deployment
+	//   metadata:
+	//     name: nginx
+	//   spec:
+	//     template:
+	//       spec:
+	//         containers:
+	//         - name: nginx
+	//           image: nginx:1.29
+	// results:
+	// - message: runAsNonRoot must be true
+	//   severity: error
+	//   resourceRef:
+	//     apiVersion: apps/v1
+	//     kind: Deployment
+	//     name: nginx
+	//   file:
+	//     index: -1
+	//   tags:
+	//     validator: run-as-non-root
+	//
+}
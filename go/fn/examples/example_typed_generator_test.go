@@ -0,0 +1,96 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn"
+)
+
+var _ fn.Generator = &GenerateNamespaces{}
+
+// GenerateNamespaces generates a Namespace for each of the given names.
+type GenerateNamespaces struct {
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Generate is the main function logic. Different from `Runner.Run`, `items` is a pointer so that the new
+// Namespaces can be added to "ResourceList.Items". The functionConfig has been decoded to r.Namespaces.
+func (r *GenerateNamespaces) Generate(ctx *fn.Context, functionConfig *fn.KubeObject, items *fn.KubeObjects, results *fn.Results) bool {
+	for _, name := range r.Namespaces {
+		ns := fn.NewEmptyKubeObject()
+		if err := ns.SetAPIVersion("v1"); err != nil {
+			results.ErrorE(err)
+			return false
+		}
+		if err := ns.SetKind("Namespace"); err != nil {
+			results.ErrorE(err)
+			return false
+		}
+		if err := ns.SetName(name); err != nil {
+			results.ErrorE(err)
+			return false
+		}
+		items.Upsert(ns)
+	}
+	return true
+}
+
+func Example_typedGenerator() {
+	reader := strings.NewReader(`
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: GenerateNamespaces
+  metadata:
+    name: fn-config
+  namespaces:
+  - dev
+  - prod`)
+
+	var writer bytes.Buffer
+	err := fn.Execute(fn.WithGenerator(context.TODO(), &GenerateNamespaces{}), reader, &writer)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	fmt.Println(writer.String())
+
+	// Output:
+	// apiVersion: config.kubernetes.io/v1
+	// kind: ResourceList
+	// items:
+	// - apiVersion: v1
+	//   kind: Namespace
+	//   metadata:
+	//     name: dev
+	// - apiVersion: v1
+	//   kind: Namespace
+	//   metadata:
+	//     name: prod
+	// functionConfig:
+	//   apiVersion: fn.kpt.dev/v1alpha1
+	//   kind: GenerateNamespaces
+	//   metadata:
+	//     name: fn-config
+	//   namespaces:
+	//   - dev
+	//   - prod
+}
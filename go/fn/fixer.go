@@ -0,0 +1,290 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// FixedTag is the Result tag set by the Fixer on the results it has fixed.
+const FixedTag = "fn.kpt.dev/fixed"
+
+var _ ResourceListProcessor = Fixer{}
+
+// Fixer applies the Field.ProposedValue of the results to the items, so that a validator can also run in
+// auto-remediation mode, e.g.
+//
+//	fn.AsMain(fn.Fixer{Validator: fn.ResourceListProcessorFunc(validate)})
+//
+// A result is fixed if it has a ResourceRef, a Field.Path and a Field.ProposedValue. The item is matched by its
+// ResourceRef (and by its File path, if any), and the Field.Path is a dot-separated list of fields, where a list item
+// is selected by index (e.g. `containers[0]`) or by field value (e.g. `containers[name=nginx]`). A fixed result is
+// tagged with FixedTag and becomes an Info result. A fix that cannot be applied is reported as a Warning result.
+type Fixer struct {
+	// Validator produces the results to fix. Its results returned as error (Results or *Result) are added to the
+	// ResourceList results. If nil, the results of the input ResourceList are fixed, e.g. those of the previous
+	// function of the pipeline.
+	Validator ResourceListProcessor
+	// DryRun reports the fixes as Info results instead of applying them.
+	DryRun bool
+}
+
+// Process runs the Validator and fixes the results. It fails if an Error result is left unfixed, or if the
+// Validator fails without any fix.
+func (f Fixer) Process(rl *ResourceList) (bool, error) {
+	success := true
+	if f.Validator != nil {
+		s, err := f.Validator.Process(rl)
+		if err != nil {
+			if !addResults(rl, err) {
+				return false, err
+			}
+		}
+		success = s
+	}
+	fixed := 0
+	for _, result := range rl.Results {
+		if !fixable(result) {
+			continue
+		}
+		obj := findResultObject(rl.Items, result)
+		if obj == nil {
+			rl.Results = append(rl.Results, GeneralResult(fmt.Sprintf("cannot fix %q: no item matches %v",
+				result.Message, resourceRefString(result.ResourceRef)), Warning))
+			continue
+		}
+		if f.DryRun {
+			dryRun := ConfigObjectResult(fmt.Sprintf("would set %s to %s", result.Field.Path,
+				formatValue(result.Field.ProposedValue)), obj, Info)
+			dryRun.Field = result.Field
+			rl.Results = append(rl.Results, dryRun)
+			continue
+		}
+		if err := setFieldPath(obj, result.Field.Path, result.Field.ProposedValue); err != nil {
+			rl.Results = append(rl.Results, ConfigObjectResult(fmt.Sprintf("cannot fix %q: %v", result.Message, err),
+				obj, Warning))
+			continue
+		}
+		result.Severity = Info
+		if result.Tags == nil {
+			result.Tags = map[string]string{}
+		}
+		result.Tags[FixedTag] = "true"
+		fixed++
+	}
+	if rl.Results.ExitCode() != 0 {
+		return false, nil
+	}
+	return success || fixed > 0, nil
+}
+
+// addResults adds the Results (or *Result) error to the ResourceList results, unless they are already there. It
+// returns false if err is not a Results error.
+func addResults(rl *ResourceList, err error) bool {
+	var results Results
+	switch err := err.(type) {
+	case Results:
+		results = err
+	case *Result:
+		results = Results{err}
+	default:
+		return false
+	}
+	known := map[*Result]bool{}
+	for _, result := range rl.Results {
+		known[result] = true
+	}
+	for _, result := range results {
+		if !known[result] {
+			rl.Results = append(rl.Results, result)
+		}
+	}
+	return true
+}
+
+func fixable(result *Result) bool {
+	return result != nil && result.ResourceRef != nil && result.Field != nil && result.Field.Path != "" &&
+		result.Field.ProposedValue != nil && (result.Tags == nil || result.Tags[FixedTag] == "")
+}
+
+// findResultObject returns the item of the result ResourceRef and File path, or nil.
+func findResultObject(items KubeObjects, result *Result) *KubeObject {
+	ref := result.ResourceRef
+	for _, obj := range items {
+		if obj.GetAPIVersion() != ref.APIVersion || obj.GetKind() != ref.Kind || obj.GetName() != ref.Name ||
+			obj.GetNamespace() != ref.Namespace {
+			continue
+		}
+		if result.File != nil && result.File.Path != "" && obj.PathAnnotation() != "" &&
+			obj.PathAnnotation() != result.File.Path {
+			continue
+		}
+		return obj
+	}
+	return nil
+}
+
+// setFieldPath sets the value of the field path, e.g. `spec.containers[name=nginx].image`. The missing fields are
+// created, but not the missing list items.
+func setFieldPath(obj *KubeObject, path string, value any) error {
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return err
+	}
+	node := obj.obj.Node()
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch {
+		case segment.key != "":
+			if node.Kind != yaml.MappingNode {
+				return fmt.Errorf("field %q of %s is not a map", segment.key, path)
+			}
+			j := fieldIndex(node, segment.key)
+			if j < 0 {
+				if last {
+					node.Content = append(node.Content, yaml.NewStringRNode(segment.key).YNode(), &valueNode)
+					return nil
+				}
+				node.Content = append(node.Content, yaml.NewStringRNode(segment.key).YNode(),
+					&yaml.Node{Kind: yaml.MappingNode})
+				j = len(node.Content) - 2
+			}
+			if last {
+				setNode(node.Content[j+1], &valueNode)
+				return nil
+			}
+			node = node.Content[j+1]
+		default:
+			if node.Kind != yaml.SequenceNode {
+				return fmt.Errorf("list item %s of %s is not in a list", segment, path)
+			}
+			item := segment.find(node)
+			if item == nil {
+				return fmt.Errorf("list item %s of %s does not exist", segment, path)
+			}
+			if last {
+				setNode(item, &valueNode)
+				return nil
+			}
+			node = item
+		}
+	}
+	return nil
+}
+
+// setNode replaces the node with the value node, and keeps its comments.
+func setNode(node, value *yaml.Node) {
+	headComment, lineComment, footComment := node.HeadComment, node.LineComment, node.FootComment
+	*node = *value
+	node.HeadComment, node.LineComment, node.FootComment = headComment, lineComment, footComment
+}
+
+func fieldIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// pathSegment is a map key, a list index or a list item selector `field=value` of a field path.
+type pathSegment struct {
+	key   string
+	index int
+	field string
+	value string
+}
+
+func (s pathSegment) String() string {
+	if s.field != "" {
+		return fmt.Sprintf("[%s=%s]", s.field, s.value)
+	}
+	return fmt.Sprintf("[%d]", s.index)
+}
+
+// find returns the list item of the segment, or nil.
+func (s pathSegment) find(list *yaml.Node) *yaml.Node {
+	if s.field == "" {
+		if s.index < len(list.Content) {
+			return list.Content[s.index]
+		}
+		return nil
+	}
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if j := fieldIndex(item, s.field); j >= 0 && item.Content[j+1].Value == s.value {
+			return item
+		}
+	}
+	return nil
+}
+
+func parseFieldPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, pathSegment{key: key})
+		}
+		for rest != "" {
+			var selector string
+			var found bool
+			if selector, rest, found = strings.Cut(rest, "]"); !found {
+				return nil, fmt.Errorf("invalid field path %q: missing ]", path)
+			}
+			rest = strings.TrimPrefix(rest, "[")
+			if field, value, found := strings.Cut(selector, "="); found {
+				segments = append(segments, pathSegment{field: field, value: value})
+				continue
+			}
+			index, err := strconv.Atoi(selector)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid field path %q: invalid list index %q", path, selector)
+			}
+			segments = append(segments, pathSegment{index: index})
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid field path %q", path)
+	}
+	return segments, nil
+}
+
+// formatValue formats the value of a field the way it is written in YAML flow style, quoted if needed, e.g. "yes".
+func formatValue(v any) string {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		node.Style = yaml.FlowStyle
+	}
+	b, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
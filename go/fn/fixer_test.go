@@ -0,0 +1,204 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fixerInput = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+  spec:
+    replicas: 1 # too few
+    template:
+      spec:
+        containers:
+        - name: nginx
+          image: nginx:latest
+`
+
+// minReplicas is a validator which proposes fixes.
+func minReplicas(rl *ResourceList) (bool, error) {
+	var results Results
+	for _, obj := range rl.Items {
+		spec := obj.GetMap("spec")
+		if spec.GetInt("replicas") < 3 {
+			result := SubObjectResult("replicas must be at least 3", spec, "replicas", Error)
+			result.Field.ProposedValue = 3
+			results = append(results, result)
+		}
+		container := spec.GetMap("template").GetMap("spec").GetSlice("containers")[0]
+		result := SubObjectResult("the image must be pinned", container, "image", Warning)
+		result.Field.Path = "spec.template.spec.containers[name=nginx].image"
+		result.Field.ProposedValue = "nginx:1.29"
+		results = append(results, result)
+		result = SubObjectResult("resources must be set", container, "resources", Error)
+		result.Field.ProposedValue = map[string]any{"limits": map[string]any{"memory": "64Mi"}}
+		results = append(results, result)
+	}
+	return true, results
+}
+
+func TestFixer(t *testing.T) {
+	fixedItem := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # too few
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.29
+        resources:
+          limits:
+            memory: 64Mi
+`
+	inputItem := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 1 # too few
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:latest
+`
+	testcases := []struct {
+		name            string
+		fixer           Fixer
+		input           string
+		expectedItem    string
+		expectedResults []string
+		expectedError   string
+	}{
+		{
+			name:         "fix",
+			fixer:        Fixer{Validator: ResourceListProcessorFunc(minReplicas)},
+			input:        fixerInput,
+			expectedItem: fixedItem,
+			expectedResults: []string{
+				"[info] apps/v1/Deployment/nginx spec.replicas: replicas must be at least 3",
+				"[info] apps/v1/Deployment/nginx spec.template.spec.containers[name=nginx].image: the image must be pinned",
+				"[info] apps/v1/Deployment/nginx spec.template.spec.containers[0].resources: resources must be set",
+			},
+		},
+		{
+			name:         "dry run",
+			fixer:        Fixer{Validator: ResourceListProcessorFunc(minReplicas), DryRun: true},
+			input:        fixerInput,
+			expectedItem: inputItem,
+			expectedResults: []string{
+				"[error] apps/v1/Deployment/nginx spec.replicas: replicas must be at least 3",
+				"[warning] apps/v1/Deployment/nginx spec.template.spec.containers[name=nginx].image: the image must be pinned",
+				"[error] apps/v1/Deployment/nginx spec.template.spec.containers[0].resources: resources must be set",
+				"[info] apps/v1/Deployment/nginx spec.replicas: would set spec.replicas to 3",
+				"[info] apps/v1/Deployment/nginx spec.template.spec.containers[name=nginx].image: would set spec.template.spec.containers[name=nginx].image to nginx:1.29",
+				`[info] apps/v1/Deployment/nginx spec.template.spec.containers[0].resources: would set spec.template.spec.containers[0].resources to {limits: {memory: 64Mi}}`,
+			},
+			expectedError: "error: function failure",
+		},
+		{
+			name:  "input results",
+			fixer: Fixer{},
+			input: fixerInput + `results:
+- message: replicas must be at least 3
+  severity: error
+  resourceRef: {apiVersion: apps/v1, kind: Deployment, name: nginx}
+  field: {path: spec.replicas, proposedValue: 3}
+- message: the image must be pinned
+  severity: warning
+  resourceRef: {apiVersion: apps/v1, kind: Deployment, name: nginx}
+  field: {path: "spec.template.spec.containers[1].image", proposedValue: "nginx:1.29"}
+- message: already fixed
+  severity: info
+  resourceRef: {apiVersion: apps/v1, kind: Deployment, name: nginx}
+  field: {path: spec.replicas, proposedValue: 5}
+  tags: {fn.kpt.dev/fixed: "true"}
+- message: unknown resource
+  severity: error
+  resourceRef: {apiVersion: v1, kind: Service, name: nginx}
+  field: {path: spec.type, proposedValue: ClusterIP}
+`,
+			expectedItem: strings.Replace(inputItem, "replicas: 1", "replicas: 3", 1),
+			expectedResults: []string{
+				"[info] apps/v1/Deployment/nginx spec.replicas: replicas must be at least 3",
+				"[warning] apps/v1/Deployment/nginx spec.template.spec.containers[1].image: the image must be pinned",
+				"[info] apps/v1/Deployment/nginx spec.replicas: already fixed",
+				"[error] v1/Service/nginx spec.type: unknown resource",
+				`[warning] apps/v1/Deployment/nginx: cannot fix "the image must be pinned": list item [1] of spec.template.spec.containers[1].image does not exist`,
+				`[warning]: cannot fix "unknown resource": no item matches v1/Service/nginx`,
+			},
+			expectedError: "error: function failure",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Run(tc.fixer, []byte(tc.input))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			rl, err := ParseResourceList(out)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedItem, rl.Items[0].String())
+			var results []string
+			for _, result := range rl.Results {
+				results = append(results, result.String())
+			}
+			assert.Equal(t, tc.expectedResults, results)
+		})
+	}
+}
+
+func TestParseFieldPath(t *testing.T) {
+	testcases := []struct {
+		path          string
+		expected      []pathSegment
+		expectedError string
+	}{
+		{path: "spec.replicas", expected: []pathSegment{{key: "spec"}, {key: "replicas"}}},
+		{
+			path: ".spec.containers[name=nginx].args[1]",
+			expected: []pathSegment{
+				{key: "spec"}, {key: "containers"}, {field: "name", value: "nginx"}, {key: "args"}, {index: 1},
+			},
+		},
+		{path: "spec.containers[0", expectedError: `invalid field path "spec.containers[0": missing ]`},
+		{path: "spec.containers[-1]", expectedError: `invalid field path "spec.containers[-1]": invalid list index "-1"`},
+		{path: "", expectedError: `invalid field path ""`},
+	}
+	for _, tc := range testcases {
+		segments, err := parseFieldPath(tc.path)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.path)
+			continue
+		}
+		assert.NoError(t, err, tc.path)
+		assert.Equal(t, tc.expected, segments, tc.path)
+	}
+}
@@ -20,9 +20,24 @@ type Runner interface {
 	//    items: The KRM resources in the form of a slice of KubeObject.
 	//       Note: You can only modify the existing items but not add or delete items.
 	//       We intentionally design the method this way to make the Runner be used as a Transformer or Validator, but not a Generator.
+	//       Use the Generator interface if you need to add or delete items.
 	//    results: You can use `ErrorE` `Errorf` `Infof` `Warningf` `WarningE` to add user message to `Results`.
 	// Returns:
 	//    return a boolean to tell whether the execution should be considered as PASS or FAIL. CLI like kpt will
 	// display the corresponding message.
 	Run(context *Context, functionConfig *KubeObject, items KubeObjects, results *Results) bool
 }
+
+// Generator is a Runner variant that can add and delete items. It gets the same functionConfig decoding as a Runner
+// when wrapped by WithGenerator.
+type Generator interface {
+	// Generate provides the entrypoint to allow you make changes to input `resourcelist.Items`
+	// Args:
+	//    items: A pointer to the KRM resources in the form of a slice of KubeObject. You can modify, add or
+	//       delete items, e.g. via `items.Upsert` or by assigning a new slice to `*items`.
+	//    results: You can use `ErrorE` `Errorf` `Infof` `Warningf` `WarningE` to add user message to `Results`.
+	// Returns:
+	//    return a boolean to tell whether the execution should be considered as PASS or FAIL. CLI like kpt will
+	// display the corresponding message.
+	Generate(context *Context, functionConfig *KubeObject, items *KubeObjects, results *Results) bool
+}
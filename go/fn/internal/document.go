@@ -17,6 +17,8 @@ package internal
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -67,6 +69,79 @@ func (d *doc) ToYAML() ([]byte, error) {
 	return w.Bytes(), nil
 }
 
+// ToJSON encodes each node as an indented JSON document. Unlike yaml.RNode.MarshalJSON, it keeps the order of the
+// map fields.
+func (d *doc) ToJSON() ([]byte, error) {
+	var w bytes.Buffer
+	for _, node := range d.nodes {
+		var compact bytes.Buffer
+		if err := writeJSON(&compact, node); err != nil {
+			return nil, err
+		}
+		if err := json.Indent(&w, compact.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		w.WriteString("\n")
+	}
+	return w.Bytes(), nil
+}
+
+func writeJSON(w *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			w.WriteString("null")
+			return nil
+		}
+		return writeJSON(w, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(w, node.Alias)
+	case yaml.MappingNode:
+		w.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				w.WriteString(",")
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			w.Write(key)
+			w.WriteString(":")
+			if err = writeJSON(w, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		w.WriteString("}")
+	case yaml.SequenceNode:
+		w.WriteString("[")
+		for i, item := range node.Content {
+			if i > 0 {
+				w.WriteString(",")
+			}
+			if err := writeJSON(w, item); err != nil {
+				return err
+			}
+		}
+		w.WriteString("]")
+	case yaml.ScalarNode:
+		var v interface{}
+		if node.ShortTag() == yaml.NodeTagString {
+			v = node.Value
+		} else if err := node.Decode(&v); err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("unable to encode %q as JSON: %w", node.Value, err)
+		}
+		w.Write(b)
+	default:
+		return fmt.Errorf("unsupported yaml node kind %v", node.Kind)
+	}
+	return nil
+}
+
 func (d *doc) Elements() ([]*MapVariant, error) {
 	return ExtractObjects(d.nodes...)
 }
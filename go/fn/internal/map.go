@@ -94,6 +94,15 @@ func (o *MapVariant) Entries() (map[string]variant, error) {
 	return entries, nil
 }
 
+// Field returns the key and the value nodes of the field, or false if the field does not exist.
+func (o *MapVariant) Field(key string) (*yaml.Node, *yaml.Node, bool) {
+	i := findMapKey(o.node, key)
+	if i < 0 {
+		return nil, nil, false
+	}
+	return o.node.Content[i], o.node.Content[i+1], true
+}
+
 func (o *MapVariant) getVariant(key string) (variant, bool) {
 	valueNode, found := getValueNode(o.node, key)
 	if !found {
@@ -0,0 +1,161 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// JUnitGroup is how Results.ToJUnit groups the results into test cases.
+type JUnitGroup string
+
+const (
+	// GroupByResource makes a test case of the results of each resource, by ResourceRef.
+	GroupByResource JUnitGroup = "resource"
+	// GroupByFile makes a test case of the results of each file, by File.Path.
+	GroupByFile JUnitGroup = "file"
+)
+
+// packageTestCase is the name of the test case of the results without resource (or file).
+const packageTestCase = "package"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+// ToJUnit converts the results to a JUnit XML report of the test suite `name`, e.g. the function name. The results
+// are grouped into a test case per resource or per file. A test case with Error results fails, a test case with
+// Warning results passes with the warnings in its system-out, and a test case with only Info results passes. The
+// results without resource (or file) go into the test case "package" of the package-level test suite
+// `<name>.package`.
+func (r Results) ToJUnit(name string, groupBy JUnitGroup) ([]byte, error) {
+	var key func(result *Result) string
+	switch groupBy {
+	case "", GroupByResource:
+		key = func(result *Result) string {
+			if result.ResourceRef == nil {
+				return ""
+			}
+			return resourceRefString(result.ResourceRef)
+		}
+	case GroupByFile:
+		key = func(result *Result) string {
+			if result.File == nil {
+				return ""
+			}
+			return result.File.Path
+		}
+	default:
+		return nil, fmt.Errorf("unknown JUnit group %q, expect %q or %q", groupBy, GroupByResource, GroupByFile)
+	}
+
+	// Group the results, in the order of their first result.
+	var keys []string
+	groups := map[string]Results{}
+	for _, result := range r {
+		if result == nil {
+			continue
+		}
+		k := key(result)
+		if _, found := groups[k]; !found && k != "" {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], result)
+	}
+
+	suites := junitTestSuites{Name: name}
+	suite := junitTestSuite{Name: name}
+	for _, k := range keys {
+		suite.add(groups[k].toJUnitTestCase(k, name))
+	}
+	suites.add(suite)
+	if packageResults := groups[""]; len(packageResults) > 0 {
+		packageSuite := junitTestSuite{Name: name + "." + packageTestCase}
+		packageSuite.add(packageResults.toJUnitTestCase(packageTestCase, packageSuite.Name))
+		suites.add(packageSuite)
+	}
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func (r Results) toJUnitTestCase(name, className string) junitTestCase {
+	testCase := junitTestCase{Name: name, ClassName: className}
+	var failures, warnings []string
+	for _, result := range r {
+		switch result.Severity {
+		case Error:
+			if testCase.Failure == nil {
+				testCase.Failure = &junitFailure{Message: result.Message, Type: string(Error)}
+			}
+			failures = append(failures, result.String())
+		case Warning:
+			warnings = append(warnings, result.String())
+		}
+	}
+	if testCase.Failure != nil {
+		testCase.Failure.Text = strings.Join(failures, "\n")
+	}
+	if len(warnings) > 0 {
+		testCase.SystemOut = &junitOutput{Text: strings.Join(warnings, "\n")}
+	}
+	return testCase
+}
+
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.TestCases = append(s.TestCases, testCase)
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+}
+
+func (s *junitTestSuites) add(suite junitTestSuite) {
+	s.Suites = append(s.Suites, suite)
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+}
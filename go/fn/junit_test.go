@@ -0,0 +1,108 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultsToJUnit(t *testing.T) {
+	deployment := &ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx"}
+	service := &ResourceRef{APIVersion: "v1", Kind: "Service", Name: "nginx"}
+	results := Results{
+		{Message: "replicas must be at least 3", Severity: Error, ResourceRef: deployment, File: &File{Path: "nginx.yaml"}},
+		{Message: "no <selector>", Severity: Warning, ResourceRef: service, File: &File{Path: "nginx.yaml", Index: 1}},
+		{Message: "image is not pinned", Severity: Error, ResourceRef: deployment, File: &File{Path: "nginx.yaml"}},
+		{Message: "valid", Severity: Info, ResourceRef: &ResourceRef{APIVersion: "v1", Kind: "ConfigMap", Name: "cm"},
+			File: &File{Path: "cm.yaml"}},
+		{Message: "3 resources validated", Severity: Info},
+	}
+	testcases := []struct {
+		name          string
+		results       Results
+		groupBy       JUnitGroup
+		expected      string
+		expectedError string
+	}{
+		{
+			name:    "by resource",
+			results: results,
+			groupBy: GroupByResource,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="validate" tests="4" failures="1">
+  <testsuite name="validate" tests="3" failures="1">
+    <testcase name="apps/v1/Deployment/nginx" classname="validate">
+      <failure message="replicas must be at least 3" type="error"><![CDATA[[error] apps/v1/Deployment/nginx: replicas must be at least 3
+[error] apps/v1/Deployment/nginx: image is not pinned]]></failure>
+    </testcase>
+    <testcase name="v1/Service/nginx" classname="validate">
+      <system-out><![CDATA[[warning] v1/Service/nginx: no <selector>]]></system-out>
+    </testcase>
+    <testcase name="v1/ConfigMap/cm" classname="validate"></testcase>
+  </testsuite>
+  <testsuite name="validate.package" tests="1" failures="0">
+    <testcase name="package" classname="validate.package"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:    "by file",
+			results: results,
+			groupBy: GroupByFile,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="validate" tests="3" failures="1">
+  <testsuite name="validate" tests="2" failures="1">
+    <testcase name="nginx.yaml" classname="validate">
+      <failure message="replicas must be at least 3" type="error"><![CDATA[[error] apps/v1/Deployment/nginx: replicas must be at least 3
+[error] apps/v1/Deployment/nginx: image is not pinned]]></failure>
+      <system-out><![CDATA[[warning] v1/Service/nginx: no <selector>]]></system-out>
+    </testcase>
+    <testcase name="cm.yaml" classname="validate"></testcase>
+  </testsuite>
+  <testsuite name="validate.package" tests="1" failures="0">
+    <testcase name="package" classname="validate.package"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "no results",
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="validate" tests="0" failures="0">
+  <testsuite name="validate" tests="0" failures="0"></testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:          "unknown group",
+			groupBy:       "namespace",
+			expectedError: `unknown JUnit group "namespace", expect "resource" or "file"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.results.ToJUnit("validate", tc.groupBy)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+)
+
+const (
+	// InputDirFlag makes AsMain read the resources from a local directory instead of a ResourceList from STDIN,
+	// the same way as `kpt fn source` does.
+	InputDirFlag = "--input-dir"
+	// OutputDirFlag makes AsMain write the resources to a local directory instead of a ResourceList to STDOUT,
+	// the same way as `kpt fn sink` does. The results are printed to STDERR.
+	OutputDirFlag = "--output-dir"
+	// ConfigFlag gives AsMain the file of the functionConfig, which overrides the ResourceList.FunctionConfig.
+	ConfigFlag = "--config"
+)
+
+// localFlags are the AsMain flags to run a function on a local directory, without kpt.
+type localFlags struct {
+	inputDir   string
+	outputDir  string
+	configFile string
+}
+
+func parseLocalFlags(args []string) localFlags {
+	var flags localFlags
+	flags.inputDir, _ = lookupArg(args, InputDirFlag)
+	flags.outputDir, _ = lookupArg(args, OutputDirFlag)
+	flags.configFile, _ = lookupArg(args, ConfigFlag)
+	return flags
+}
+
+func (f localFlags) enabled() bool {
+	return f.inputDir != "" || f.outputDir != "" || f.configFile != ""
+}
+
+// lookupArg returns the value of the `--name=value` or `--name value` argument. The other arguments are ignored, so
+// that AsMain also runs in tests, where os.Args holds the `go test` flags.
+func lookupArg(args []string, name string) (string, bool) {
+	for i, arg := range args {
+		if value, found := strings.CutPrefix(arg, name+"="); found {
+			return value, true
+		}
+		if arg == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// localReadWriter reads the ResourceList from the local directory or from `in`, and writes it to the local
+// directory or to `out`.
+type localReadWriter struct {
+	localFlags
+	out io.Writer
+	// stdio reads and writes the ResourceList when no directory is given.
+	stdio *byteReadWriter
+	// inputFiles are the files read from the input directory.
+	inputFiles []string
+}
+
+func newLocalReadWriter(flags localFlags, in io.Reader, out io.Writer) *localReadWriter {
+	return &localReadWriter{
+		localFlags: flags,
+		out:        out,
+		stdio:      newByteReadWriter(in, out),
+	}
+}
+
+func (rw *localReadWriter) Read() (*ResourceList, error) {
+	var rl *ResourceList
+	if rw.inputDir == "" {
+		var err error
+		if rl, err = rw.stdio.Read(); err != nil {
+			return nil, err
+		}
+	} else {
+		items, err := ReadKubeObjectsFromDirectory(rw.inputDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			rw.inputFiles = append(rw.inputFiles, PathOfKubeObject(item))
+		}
+		rl = &ResourceList{Items: items, FunctionConfig: NewEmptyKubeObject()}
+	}
+	if rw.configFile != "" {
+		b, err := os.ReadFile(rw.configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the functionConfig: %w", err)
+		}
+		if rl.FunctionConfig, err = ParseKubeObject(b); err != nil {
+			return nil, fmt.Errorf("failed to parse the functionConfig %q: %w", rw.configFile, err)
+		}
+	}
+	return rl, nil
+}
+
+func (rw *localReadWriter) Write(rl *ResourceList) error {
+	if rw.outputDir == "" {
+		toBytes := rl.ToYAML
+		if rw.inputDir == "" && rw.stdio.json {
+			toBytes = rl.ToJSON
+		}
+		out, err := toBytes()
+		if err != nil {
+			return err
+		}
+		_, err = rw.out.Write(out)
+		return err
+	}
+	if err := writeKubeObjectsToDirectory(rw.outputDir, rl.Items); err != nil {
+		return err
+	}
+	if rw.inputDir != "" && sameDir(rw.inputDir, rw.outputDir) {
+		if err := removeEmptiedFiles(rw.outputDir, rw.inputFiles, rl.Items); err != nil {
+			return err
+		}
+	}
+	logResults(rl.Results, rl.Items)
+	return nil
+}
+
+// writeKubeObjectsToDirectory writes the objects to the files of their path annotations, in the order of their
+// index annotations.
+func writeKubeObjectsToDirectory(dir string, objs KubeObjects) error {
+	files := map[string]KubeObjects{}
+	for _, obj := range objs {
+		path := PathOfKubeObject(obj)
+		if !filepath.IsLocal(path) {
+			return fmt.Errorf("%v has path %q outside of the output directory", obj.ShortString(), path)
+		}
+		files[path] = append(files[path], obj)
+	}
+	for path, fileObjs := range files {
+		sort.SliceStable(fileObjs, func(i, j int) bool {
+			return indexOfKubeObject(fileObjs[i]) < indexOfKubeObject(fileObjs[j])
+		})
+		// The index annotations only make sense within the ResourceList.
+		for _, obj := range fileObjs {
+			if err := obj.RemoveAnnotation(kioutil.IndexAnnotation); err != nil {
+				return err
+			}
+			if err := obj.RemoveAnnotation(kioutil.LegacyIndexAnnotation); err != nil { //nolint:staticcheck //SA1019
+				return err
+			}
+		}
+		content, err := WriteKubeObjectsToString(fileObjs)
+		if err != nil {
+			return err
+		}
+		fullPath := filepath.Join(dir, path)
+		if err = os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEmptiedFiles removes the input files whose objects have all been deleted by the function.
+func removeEmptiedFiles(dir string, inputFiles []string, objs KubeObjects) error {
+	remaining := map[string]bool{}
+	for _, obj := range objs {
+		remaining[PathOfKubeObject(obj)] = true
+	}
+	for _, path := range inputFiles {
+		if remaining[path] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, path)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
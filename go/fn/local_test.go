@@ -0,0 +1,135 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// labelAndPrune sets the functionConfig `data.team` label to the items and deletes the `prune-me` ones.
+func labelAndPrune(rl *ResourceList) (bool, error) {
+	team, _, _ := rl.FunctionConfig.NestedString("data", "team")
+	var items KubeObjects
+	for _, item := range rl.Items {
+		if item.GetName() == "prune-me" {
+			continue
+		}
+		if err := item.SetLabel("team", team); err != nil {
+			return false, err
+		}
+		items = append(items, item)
+	}
+	rl.Items = items
+	return true, nil
+}
+
+func TestAsMainLocalDirectory(t *testing.T) {
+	files := map[string]string{
+		"fn-config.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: fn-config
+data:
+  team: sre
+`,
+		"pkg/cms.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b # keep the comments
+data:
+  k: v
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`,
+		"pkg/nested/prune.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: prune-me
+`,
+	}
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b # keep the comments
+  labels:
+    team: sre
+data:
+  k: v
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  labels:
+    team: sre
+`
+
+	testcases := map[string]struct {
+		outputDir   string
+		prunedExist bool
+	}{
+		"in place": {
+			outputDir: "pkg",
+		},
+		"another directory": {
+			outputDir:   "out",
+			prunedExist: true,
+		},
+	}
+	for name, test := range testcases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range files {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+			}
+			origArgs := os.Args
+			os.Args = []string{"fn", InputDirFlag, filepath.Join(dir, "pkg"),
+				OutputDirFlag + "=" + filepath.Join(dir, test.outputDir), ConfigFlag + "=" + filepath.Join(dir, "fn-config.yaml")}
+			defer func() { os.Args = origArgs }()
+
+			out := asMainWithStdio(t, ResourceListProcessorFunc(labelAndPrune), "")
+			assert.Empty(t, out)
+			actual, err := os.ReadFile(filepath.Join(dir, test.outputDir, "cms.yaml"))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(actual))
+			_, err = os.Stat(filepath.Join(dir, "pkg/nested/prune.yaml"))
+			assert.Equal(t, test.prunedExist, err == nil)
+			if test.outputDir != "pkg" {
+				_, err = os.Stat(filepath.Join(dir, test.outputDir, "nested/prune.yaml"))
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestLookupArg(t *testing.T) {
+	args := []string{"-test.v", "--input-dir", "pkg", "--config=fn.yaml", "--output-dir"}
+	value, found := lookupArg(args, InputDirFlag)
+	assert.True(t, found)
+	assert.Equal(t, "pkg", value)
+	value, found = lookupArg(args, ConfigFlag)
+	assert.True(t, found)
+	assert.Equal(t, "fn.yaml", value)
+	_, found = lookupArg(args, OutputDirFlag)
+	assert.False(t, found)
+}
@@ -15,8 +15,12 @@
 package fn
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"slices"
+	"sync"
 )
 
 func Log(in ...interface{}) {
@@ -26,3 +30,194 @@ func Log(in ...interface{}) {
 func Logf(format string, in ...interface{}) {
 	fmt.Fprintf(os.Stderr, format, in...)
 }
+
+const (
+	// LogLevelEnv is the environment variable of the level of the function logger, e.g. `debug`, `info` (the
+	// default), `warn` or `error`.
+	LogLevelEnv = "KRM_FN_LOG_LEVEL"
+	// LogResourceKey is the key of the log attribute of the resource a log record is about. See ResourceAttr.
+	LogResourceKey = "resource"
+)
+
+// LogValue logs the ResourceRef as a group of its apiVersion, kind, namespace and name.
+func (r ResourceRef) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("apiVersion", r.APIVersion), slog.String("kind", r.Kind)}
+	if r.Namespace != "" {
+		attrs = append(attrs, slog.String("namespace", r.Namespace))
+	}
+	return slog.GroupValue(append(attrs, slog.String("name", r.Name))...)
+}
+
+// ResourceAttr returns the log attribute of the resource a log record is about, e.g.
+//
+//	ctx.Logger().Warn("no resource limits", fn.ResourceAttr(obj))
+//
+// The results mirrored from the log records (see WithLogResults) have the ResourceRef of this attribute.
+func ResourceAttr(obj *KubeObject) slog.Attr {
+	return slog.Any(LogResourceKey, ResourceRef{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+	})
+}
+
+// WithLogResults mirrors the Warn and Error records of the function logger (see ResourceList.Logger) into Warning
+// and Error results. The result has the ResourceRef of the LogResourceKey attribute, and the other attributes as
+// tags. The records are mirrored even if the LogLevelEnv hides them.
+func WithLogResults() Option {
+	return func(o *options) {
+		o.logResults = true
+	}
+}
+
+// Logger returns the structured logger of the evaluation. It writes to STDERR the records at or above the level of
+// the LogLevelEnv, and mirrors the Warn and Error records into the ResourceList results with WithLogResults.
+func (rl *ResourceList) Logger() *slog.Logger {
+	handler := newStderrHandler()
+	if rl.logResults {
+		handler = &resultHandler{Handler: handler, rl: rl}
+	}
+	return slog.New(handler)
+}
+
+// setLogResults sets whether the Logger records are mirrored into the Results. See WithLogResults.
+func (rl *ResourceList) setLogResults(enabled bool) {
+	rl.logResults = enabled
+	if enabled {
+		rl.logMu = &sync.Mutex{}
+	}
+}
+
+// Logger returns the structured logger of the Runner (or Generator). See ResourceList.Logger.
+func (c *Context) Logger() *slog.Logger {
+	if c.rl == nil {
+		return slog.New(newStderrHandler())
+	}
+	return c.rl.Logger()
+}
+
+func newStderrHandler() slog.Handler {
+	return slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel()})
+}
+
+// logLevel returns the level of the LogLevelEnv, or slog.LevelInfo if it is unset or invalid.
+func logLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv(LogLevelEnv))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// resultHandler mirrors the Warn and Error records into the ResourceList results, and passes the records enabled by
+// the Handler to it.
+type resultHandler struct {
+	slog.Handler
+	rl *ResourceList
+	// attrs are the attributes of the logger, with the prefix of their groups.
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *resultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || h.Handler.Enabled(ctx, level)
+}
+
+func (h *resultHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		result := &Result{Message: record.Message, Severity: Warning}
+		if record.Level >= slog.LevelError {
+			result.Severity = Error
+		}
+		attrs := slices.Clone(h.attrs)
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs = append(attrs, slog.Attr{Key: h.prefix + attr.Key, Value: attr.Value})
+			return true
+		})
+		for _, attr := range attrs {
+			addLogAttr(result, attr.Key, attr.Value)
+		}
+		h.rl.logMu.Lock()
+		h.rl.Results = append(h.rl.Results, result)
+		h.rl.logMu.Unlock()
+	}
+	if !h.Handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *resultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.Handler = h.Handler.WithAttrs(attrs)
+	c.attrs = slices.Clone(h.attrs)
+	for _, attr := range attrs {
+		c.attrs = append(c.attrs, slog.Attr{Key: h.prefix + attr.Key, Value: attr.Value})
+	}
+	return &c
+}
+
+func (h *resultHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.Handler = h.Handler.WithGroup(name)
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+// addLogAttr adds the log attribute to the result, as its ResourceRef or as a tag. The attributes of a group are
+// added with the prefix of the group.
+func addLogAttr(result *Result, key string, value slog.Value) {
+	if key == LogResourceKey {
+		if ref := logResourceRef(value); ref != nil {
+			result.ResourceRef = ref
+			return
+		}
+	}
+	value = value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, attr := range value.Group() {
+			addLogAttr(result, key+"."+attr.Key, attr.Value)
+		}
+		return
+	}
+	if result.Tags == nil {
+		result.Tags = map[string]string{}
+	}
+	result.Tags[key] = value.String()
+}
+
+// logResourceRef returns the ResourceRef of the value of the LogResourceKey attribute, which is a ResourceRef or a
+// group of its fields, or nil.
+func logResourceRef(value slog.Value) *ResourceRef {
+	switch ref := value.Any().(type) {
+	case ResourceRef:
+		return &ref
+	case *ResourceRef:
+		return ref
+	}
+	value = value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return nil
+	}
+	ref := &ResourceRef{}
+	for _, attr := range value.Group() {
+		switch attr.Key {
+		case "apiVersion":
+			ref.APIVersion = attr.Value.String()
+		case "kind":
+			ref.Kind = attr.Value.String()
+		case "namespace":
+			ref.Namespace = attr.Value.String()
+		case "name":
+			ref.Name = attr.Value.String()
+		}
+	}
+	if ref.Kind == "" && ref.Name == "" {
+		return nil
+	}
+	return ref
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStderr returns what f writes to STDERR.
+func captureStderr(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+	f()
+	assert.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestLogger(t *testing.T) {
+	input := []byte(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+    namespace: default
+`)
+	ref := &ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", Namespace: "default"}
+	log := func(ctx *Context, obj *KubeObject) {
+		logger := ctx.Logger()
+		logger.Debug("checking", ResourceAttr(obj))
+		logger.Info("checked", "count", 1)
+		logger.Warn("no resource limits", ResourceAttr(obj), "rule", "limits")
+		logger.With("rule", "replicas").Error("too few replicas",
+			slog.Group(LogResourceKey, "apiVersion", "apps/v1", "kind", "Deployment", "name", "nginx",
+				"namespace", "default"), slog.Group("spec", "replicas", 1))
+	}
+
+	testcases := []struct {
+		name            string
+		level           string
+		opts            []Option
+		expectedResults Results
+		expectedLog     []string
+		unexpectedLog   []string
+	}{
+		{
+			name:          "default level",
+			expectedLog:   []string{"level=INFO msg=checked count=1", "level=WARN msg=\"no resource limits\""},
+			unexpectedLog: []string{"checking"},
+		},
+		{
+			name:  "debug level",
+			level: "debug",
+			expectedLog: []string{
+				"level=DEBUG msg=checking resource.apiVersion=apps/v1 resource.kind=Deployment " +
+					"resource.namespace=default resource.name=nginx",
+			},
+		},
+		{
+			name:          "error level with results",
+			level:         "error",
+			opts:          []Option{WithLogResults()},
+			expectedLog:   []string{"level=ERROR msg=\"too few replicas\" rule=replicas resource.apiVersion=apps/v1"},
+			unexpectedLog: []string{"checked", "no resource limits"},
+			expectedResults: Results{
+				{Message: "no resource limits", Severity: Warning, ResourceRef: ref, Tags: map[string]string{"rule": "limits"}},
+				{
+					Message: "too few replicas", Severity: Error, ResourceRef: ref,
+					Tags: map[string]string{"rule": "replicas", "spec.replicas": "1"},
+				},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(LogLevelEnv, tc.level)
+			var rl *ResourceList
+			p := ResourceListProcessorFunc(func(in *ResourceList) (bool, error) {
+				rl = in
+				return WithContext(context.Background(), runnerFunc(func(ctx *Context, _ *Results) bool {
+					log(ctx, in.Items[0])
+					return true
+				})).Process(in)
+			})
+			stderr := captureStderr(t, func() {
+				_, err := Run(p, input, tc.opts...)
+				assert.NoError(t, err)
+			})
+			for _, expected := range tc.expectedLog {
+				assert.Contains(t, stderr, expected)
+			}
+			for _, unexpected := range tc.unexpectedLog {
+				assert.NotContains(t, stderr, unexpected)
+			}
+			// The first result is about the missing functionConfig.
+			if len(tc.expectedResults) == 0 {
+				assert.Len(t, rl.Results, 1)
+				return
+			}
+			assert.Equal(t, tc.expectedResults, rl.Results[1:])
+		})
+	}
+}
+
+func TestLoggerConcurrent(t *testing.T) {
+	rl := &ResourceList{}
+	rl.setLogResults(true)
+	// The attributes of the shared logger have a spare capacity, which the records must not write to.
+	shared := rl.Logger().With("a", 1).With("b", 2, "c", 3)
+	captureStderr(t, func() {
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				shared.Warn(strconv.Itoa(i), "i", i)
+			}()
+			go func() {
+				defer wg.Done()
+				rl.Logger().Warn(strconv.Itoa(i), "i", i)
+			}()
+		}
+		wg.Wait()
+	})
+	assert.Len(t, rl.Results, 20)
+	for _, result := range rl.Results {
+		assert.Equal(t, result.Message, result.Tags["i"])
+	}
+}
+
+func TestLogResourceRef(t *testing.T) {
+	ref := ResourceRef{APIVersion: "v1", Kind: "ConfigMap", Name: "example"}
+	testcases := []struct {
+		name     string
+		value    slog.Value
+		expected *ResourceRef
+	}{
+		{name: "ResourceRef", value: slog.AnyValue(ref), expected: &ref},
+		{name: "ResourceRef pointer", value: slog.AnyValue(&ref), expected: &ref},
+		{name: "group", value: ref.LogValue(), expected: &ref},
+		{name: "string", value: slog.StringValue("v1/ConfigMap/example")},
+		{name: "unrelated group", value: slog.GroupValue(slog.Int("count", 1))},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, logResourceRef(tc.value))
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Middleware wraps a ResourceListProcessor with the processing common to many functions, e.g. logging or panic
+// recovery. A Middleware calls `next.Process` to process the ResourceList. The processor it returns should also have
+// an `Unwrap() ResourceListProcessor` method returning `next`, as those of the built-in middlewares do, so that
+// Concurrent and NewFunctionMetadata find the Runner (or Generator) it wraps.
+type Middleware func(next ResourceListProcessor) ResourceListProcessor
+
+// wrappedProcessor is the processor of a built-in Middleware around the next processor.
+type wrappedProcessor struct {
+	next    ResourceListProcessor
+	process ResourceListProcessorFunc
+}
+
+func wrap(next ResourceListProcessor, process ResourceListProcessorFunc) ResourceListProcessor {
+	return wrappedProcessor{next: next, process: process}
+}
+
+func (p wrappedProcessor) Process(rl *ResourceList) (bool, error) {
+	return p.process(rl)
+}
+
+// Unwrap returns the processor wrapped by the middleware.
+func (p wrappedProcessor) Unwrap() ResourceListProcessor {
+	return p.next
+}
+
+// unwrapRunner returns the processor of the Runner (or Generator) which p is, or wraps with middlewares.
+func unwrapRunner(p any) (runnerProcessor, bool) {
+	for {
+		switch w := p.(type) {
+		case runnerProcessor:
+			return w, true
+		case interface{ Unwrap() ResourceListProcessor }:
+			p = w.Unwrap()
+		default:
+			return runnerProcessor{}, false
+		}
+	}
+}
+
+// Use wraps p with the middlewares. The first middleware is the outermost one, e.g.
+//
+//	fn.AsMain(fn.Use(validator, fn.Logging("validator"), fn.Recover(), fn.ReadOnly()))
+//
+// runs the Logging, then the Recover, then the ReadOnly middleware around the validator.
+func Use(p ResourceListProcessor, middlewares ...Middleware) ResourceListProcessor {
+	return Compose(middlewares...)(p)
+}
+
+// Compose returns the Middleware of the middlewares, the first being the outermost one. See Use.
+func Compose(middlewares ...Middleware) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// WithMiddleware wraps the processor of AsMain, Execute or Run with the middlewares (see Use), e.g. for a Runner
+// given to AsMain.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// Logging logs the processing of the processor `name` with the ResourceList Logger: a Debug record when it starts,
+// and an Info record when it ends, with its success, error, duration and number of new results.
+func Logging(name string) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (bool, error) {
+			logger := rl.Logger().With("processor", name)
+			logger.Debug("processing", "items", len(rl.Items))
+			results, start := len(rl.Results), time.Now()
+			success, err := next.Process(rl)
+			attrs := []any{"success", success, "duration", time.Since(start), "items", len(rl.Items),
+				"results", len(rl.Results) - results}
+			if err != nil {
+				attrs = append(attrs, "error", err)
+			}
+			logger.Info("processed", attrs...)
+			return success, err
+		})
+	}
+}
+
+// Timing calls observe with the duration of each processing, e.g. to export it as a metric. observe may be nil. The
+// processing is also a span `name` of the trace, if any (see TraceFileEnv).
+func Timing(name string, observe func(d time.Duration)) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (success bool, err error) {
+			span := startSpan(rl, name)
+			start := time.Now()
+			defer func() {
+				if observe != nil {
+					observe(time.Since(start))
+				}
+				endSpan(rl, span, err)
+			}()
+			return next.Process(rl)
+		})
+	}
+}
+
+// Recover recovers the panics of the processor. A panic is reported as an Error result on the object being
+// processed (when known) and fails the processor, without error, so that the next processors of a Chain still run.
+// The stack trace is printed to STDERR.
+func Recover() Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (success bool, err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					panicErr := recoveredPanic(rl, v)
+					Logf("%v\n%s", panicErr, panicErr.Stack)
+					success, err = false, nil
+				}
+			}()
+			return next.Process(rl)
+		})
+	}
+}
+
+// TagResults adds the tags to the results of the processor, including the Results (or *Result) it returns as error.
+// The tags the results already have are kept.
+func TagResults(tags map[string]string) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (bool, error) {
+			before := len(rl.Results)
+			success, err := next.Process(rl)
+			results := rl.Results
+			if before <= len(results) {
+				results = results[before:]
+			}
+			switch err := err.(type) {
+			case Results:
+				results = append(results[:len(results):len(results)], err...)
+			case *Result:
+				results = append(results[:len(results):len(results)], err)
+			}
+			for _, result := range results {
+				if result == nil {
+					continue
+				}
+				if result.Tags == nil {
+					result.Tags = map[string]string{}
+				}
+				for key, value := range tags {
+					if _, found := result.Tags[key]; !found {
+						result.Tags[key] = value
+					}
+				}
+			}
+			return success, err
+		})
+	}
+}
+
+// ReadOnly fails the processor with an error if it adds, deletes or modifies the items or the functionConfig, e.g.
+// to make sure that a validator does not mutate the resources.
+func ReadOnly() Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (bool, error) {
+			items := make([]string, len(rl.Items))
+			for i, item := range rl.Items {
+				items[i] = item.String()
+			}
+			functionConfig := functionConfigString(rl)
+			success, err := next.Process(rl)
+			if err != nil {
+				return success, err
+			}
+			if len(rl.Items) != len(items) {
+				return false, fmt.Errorf("the read-only processor changed the number of items from %d to %d",
+					len(items), len(rl.Items))
+			}
+			var modified []string
+			for i, item := range rl.Items {
+				if item.String() != items[i] {
+					modified = append(modified, item.ShortString())
+				}
+			}
+			if functionConfigString(rl) != functionConfig {
+				modified = append(modified, "the functionConfig")
+			}
+			if len(modified) > 0 {
+				return false, fmt.Errorf("the read-only processor modified %s", strings.Join(modified, ", "))
+			}
+			return success, nil
+		})
+	}
+}
+
+func functionConfigString(rl *ResourceList) string {
+	if rl.FunctionConfig == nil {
+		return ""
+	}
+	return rl.FunctionConfig.String()
+}
+
+// SnapshotInput writes the input ResourceList of the processor to w in YAML, e.g. to reproduce an evaluation with
+// the same input. The items are written in their order.
+func SnapshotInput(w io.Writer) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (bool, error) {
+			snapshot := &ResourceList{
+				Items:          append(KubeObjects{}, rl.Items...),
+				FunctionConfig: rl.FunctionConfig,
+				Results:        rl.Results,
+				ItemOrder:      PreserveOrder,
+			}
+			out, err := snapshot.ToYAML()
+			if err == nil {
+				_, err = w.Write(out)
+			}
+			if err != nil {
+				return false, fmt.Errorf("failed to snapshot the input: %w", err)
+			}
+			return next.Process(rl)
+		})
+	}
+}
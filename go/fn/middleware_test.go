@@ -0,0 +1,234 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var middlewareInput = []byte(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: b
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+functionConfig:
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+`)
+
+func TestUse(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next ResourceListProcessor) ResourceListProcessor {
+			return ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				calls = append(calls, name+" before")
+				success, err := next.Process(rl)
+				calls = append(calls, name+" after")
+				return success, err
+			})
+		}
+	}
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		calls = append(calls, "processor")
+		return true, nil
+	})
+
+	_, err := Run(Use(p, trace("first"), Compose(trace("second"), trace("third"))), middlewareInput,
+		WithMiddleware(trace("option")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"option before", "first before", "second before", "third before", "processor",
+		"third after", "second after", "first after", "option after"}, calls)
+}
+
+func TestConcurrentMiddleware(t *testing.T) {
+	config := &ValidatedConfig{}
+	p := Concurrent(Use(WithContext(context.Background(), config), Recover(), Scope(IsGVK("apps", "v1", "Deployment"))))
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := fmt.Sprintf(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ValidatedConfig
+  metadata:
+    name: config
+  name: app-%c
+`, 'a'+i)
+			_, err := Run(p, []byte(input))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// The functionConfig is decoded to copies of the Runner.
+	assert.Equal(t, &ValidatedConfig{}, config)
+}
+
+func TestRecover(t *testing.T) {
+	panicking := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		return true, ApplyFnBySelector(rl, func(*KubeObject) bool { return true }, func(obj *KubeObject) error {
+			panic("boom")
+		})
+	})
+	next := false
+	p := Chain(Use(panicking, Recover()), ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		next = true
+		return true, nil
+	}))
+	rl, err := ParseResourceList(middlewareInput)
+	assert.NoError(t, err)
+
+	var success bool
+	stderr := captureStderr(t, func() {
+		success, err = p.Process(rl)
+	})
+	assert.False(t, success)
+	assert.NoError(t, err)
+	assert.True(t, next)
+	assert.Equal(t, Results{ConfigObjectResult("function panicked: boom", rl.Items[0], Error)}, rl.Results)
+	assert.Contains(t, stderr, "function panicked: boom\ngoroutine")
+}
+
+func TestTagResults(t *testing.T) {
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Infof("added")
+		rl.Results = append(rl.Results, &Result{Message: "tagged", Tags: map[string]string{"step": "own"}})
+		return false, GeneralResult("returned", Error)
+	})
+	rl, err := ParseResourceList(middlewareInput)
+	assert.NoError(t, err)
+	rl.Results = Results{GeneralResult("previous", Info)}
+
+	success, err := Use(p, TagResults(map[string]string{"step": "validate", "team": "platform"})).Process(rl)
+	assert.False(t, success)
+	tags := map[string]string{"step": "validate", "team": "platform"}
+	assert.Equal(t, &Result{Message: "returned", Severity: Error, Tags: tags}, err)
+	assert.Equal(t, Results{
+		{Message: "previous", Severity: Info},
+		{Message: "added", Severity: Info, Tags: tags},
+		{Message: "tagged", Tags: map[string]string{"step": "own", "team": "platform"}},
+	}, rl.Results)
+}
+
+func TestReadOnly(t *testing.T) {
+	testcases := []struct {
+		name        string
+		process     func(rl *ResourceList) error
+		expectedErr string
+	}{
+		{
+			name:    "unchanged",
+			process: func(rl *ResourceList) error { return nil },
+		},
+		{
+			name: "modified items",
+			process: func(rl *ResourceList) error {
+				return rl.Items[1].SetLabel("app", "example")
+			},
+			expectedErr: "the read-only processor modified Resource(apiVersion=v1, kind=ConfigMap, namespace=, name=a)",
+		},
+		{
+			name: "modified functionConfig",
+			process: func(rl *ResourceList) error {
+				return rl.FunctionConfig.SetLabel("app", "example")
+			},
+			expectedErr: "the read-only processor modified the functionConfig",
+		},
+		{
+			name: "added item",
+			process: func(rl *ResourceList) error {
+				return rl.UpsertObjectToItems(NewEmptyKubeObject(), nil, false)
+			},
+			expectedErr: "the read-only processor changed the number of items from 2 to 3",
+		},
+		{
+			name: "sorted items",
+			process: func(rl *ResourceList) error {
+				rl.Sort()
+				return nil
+			},
+			expectedErr: "the read-only processor modified Resource(apiVersion=v1, kind=ConfigMap, namespace=, " +
+				"name=a), Resource(apiVersion=v1, kind=ConfigMap, namespace=, name=b)",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rl, err := ParseResourceList(middlewareInput)
+			assert.NoError(t, err)
+			p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				return true, tc.process(rl)
+			})
+			success, err := Use(p, ReadOnly()).Process(rl)
+			if tc.expectedErr == "" {
+				assert.True(t, success)
+				assert.NoError(t, err)
+				return
+			}
+			assert.False(t, success)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSnapshotInput(t *testing.T) {
+	var snapshot bytes.Buffer
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		return true, rl.Items[0].SetLabel("app", "example")
+	})
+	out, err := Run(p, middlewareInput, WithMiddleware(SnapshotInput(&snapshot)))
+	assert.NoError(t, err)
+	assert.Equal(t, string(middlewareInput), snapshot.String())
+	assert.Contains(t, string(out), "app: example")
+}
+
+func TestLoggingAndTiming(t *testing.T) {
+	t.Setenv(LogLevelEnv, "debug")
+	var durations []time.Duration
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Infof("checked")
+		return false, fmt.Errorf("failed")
+	})
+	stderr := captureStderr(t, func() {
+		_, err := Run(p, middlewareInput, WithMiddleware(Logging("validate"), Timing("validate",
+			func(d time.Duration) { durations = append(durations, d) })))
+		assert.EqualError(t, err, "failed")
+	})
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], "level=DEBUG msg=processing processor=validate items=2")
+		assert.Contains(t, lines[1], "level=INFO msg=processed processor=validate success=false duration=")
+		assert.Contains(t, lines[1], "items=2 results=1 error=failed")
+	}
+	assert.Len(t, durations, 1)
+}
@@ -112,8 +112,8 @@ func (o *SubObject) NestedSlice(fields ...string) (SliceSubObjects, bool, error)
 		return nil, found, err
 	}
 	var val []*SubObject
-	for _, obj := range objects {
-		val = append(val, &SubObject{obj: obj})
+	for i, obj := range objects {
+		val = append(val, o.child(obj, fmt.Sprintf(".%s[%d]", strings.Join(fields, "."), i)))
 	}
 	return val, true, nil
 }
@@ -131,9 +131,7 @@ func (o *SubObject) NestedSubObject(fields ...string) (SubObject, bool, error) {
 
 	var rn yaml.RNode
 	rn.SetYNode(m.Node())
-	variant.obj = internal.NewMap(rn.YNode())
-	variant.parentGVK = o.parentGVK
-	variant.fieldpath = o.fieldpath + "." + strings.Join(fields, ".")
+	variant = *o.child(internal.NewMap(rn.YNode()), "."+strings.Join(fields, "."))
 	return variant, true, nil
 }
 
@@ -807,6 +805,17 @@ type SubObject struct {
 	parentGVK schema.GroupVersionKind
 	fieldpath string
 	obj       *internal.MapVariant
+	// root is the map of the KubeObject that the SubObject belongs to, or nil if the SubObject is the KubeObject.
+	root *internal.MapVariant
+}
+
+// child returns the SubObject of the map obj at the relative path of o.
+func (o *SubObject) child(obj *internal.MapVariant, path string) *SubObject {
+	root := o.root
+	if root == nil {
+		root = o.obj
+	}
+	return &SubObject{parentGVK: o.parentGVK, fieldpath: o.fieldpath + path, obj: obj, root: root}
 }
 
 func (o *SubObject) IsEmpty() bool {
@@ -819,7 +828,7 @@ func (o *SubObject) HasField(key string) bool {
 
 func (o *SubObject) UpsertMap(k string) *SubObject {
 	m := o.obj.UpsertMap(k)
-	return &SubObject{obj: m, parentGVK: o.parentGVK, fieldpath: o.fieldpath + "." + k}
+	return o.child(m, "."+k)
 }
 
 // Set ensures that the value of `o` (this object) is the same as `newValue“,
@@ -865,7 +874,7 @@ func (o *SubObject) GetMap(k string) *SubObject {
 		return nil
 	}
 	rn.SetYNode(val.Node())
-	return &SubObject{obj: internal.NewMap(rn.YNode()), parentGVK: o.parentGVK, fieldpath: o.fieldpath + "." + k}
+	return o.child(internal.NewMap(rn.YNode()), "."+k)
 }
 
 // GetBool accepts a single key `k` whose value is expected to be a boolean. It returns
@@ -920,3 +929,11 @@ func (s *SliceSubObjects) MarshalJSON() ([]byte, error) {
 	}
 	return yaml.NewRNode(node).MarshalJSON()
 }
+
+// rootObject returns the KubeObject that the SubObject belongs to.
+func (o *SubObject) rootObject() *KubeObject {
+	if o.root == nil {
+		return &KubeObject{*o}
+	}
+	return &KubeObject{SubObject{parentGVK: o.parentGVK, obj: o.root}}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/go-errors/errors"
 	"github.com/google/go-cmp/cmp"
+	kptfileapi "github.com/kptdev/kpt/pkg/api/kptfile/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -772,6 +773,69 @@ metadata:
     g: h`)
 }
 
+func TestMatchesSelector(t *testing.T) {
+	input := KubeObjects{}
+	for _, item := range []string{`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+  namespace: default
+  labels:
+    foo: baz
+`, `
+apiVersion: apps/v1
+kind: Service
+metadata:
+  name: example
+  namespace: my-namespace
+  labels:
+    foo: baz
+  annotations:
+    foo: bar
+`, `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: example-2
+`} {
+		obj, err := ParseKubeObject([]byte(item))
+		require.NoError(t, err)
+		input = append(input, obj)
+	}
+
+	testdata := map[string]struct {
+		selector kptfileapi.Selector
+		expected []string
+	}{
+		"empty selector": {
+			expected: []string{"Deployment", "Service", "Kptfile"},
+		},
+		"apiVersion, labels and annotations": {
+			selector: kptfileapi.Selector{
+				APIVersion:  "apps/v1",
+				Labels:      map[string]string{"foo": "baz"},
+				Annotations: map[string]string{"foo": "bar"},
+			},
+			expected: []string{"Service"},
+		},
+		"kind and name": {
+			selector: kptfileapi.Selector{Kind: "Kptfile", Name: "example-2"},
+			expected: []string{"Kptfile"},
+		},
+		"all criteria must match": {
+			selector: kptfileapi.Selector{Kind: "Deployment", Namespace: "my-namespace"},
+		},
+	}
+	for description, data := range testdata {
+		var kinds []string
+		for _, obj := range input.Where(MatchesSelector(data.selector)) {
+			kinds = append(kinds, obj.GetKind())
+		}
+		assert.Equal(t, data.expected, kinds, description)
+	}
+}
+
 func TestGetRootKptfile(t *testing.T) {
 	nestedPkgResourceList := []byte(`apiVersion: config.kubernetes.io/v1
 kind: ResourceList
@@ -205,6 +205,19 @@ func HasAnnotations(annotations map[string]string) func(*KubeObject) bool {
 	}
 }
 
+// MatchesSelector returns a function that checks if a KubeObject matches all the criteria of a Kptfile selector, e.g.
+// of a pipeline function. The empty selector matches all the KubeObjects.
+func MatchesSelector(selector kptfileapi.Selector) func(*KubeObject) bool {
+	return func(o *KubeObject) bool {
+		return (selector.APIVersion == "" || o.GetAPIVersion() == selector.APIVersion) &&
+			(selector.Kind == "" || o.GetKind() == selector.Kind) &&
+			(selector.Name == "" || o.GetName() == selector.Name) &&
+			(selector.Namespace == "" || o.GetNamespace() == selector.Namespace) &&
+			o.HasLabels(selector.Labels) &&
+			o.HasAnnotations(selector.Annotations)
+	}
+}
+
 // MoveToKubeObjects moves all yaml.RNodes into KubeObjects, leaving the original slice with empty nodes
 func MoveToKubeObjects(rns []*yaml.RNode) KubeObjects {
 	var output KubeObjects
@@ -0,0 +1,43 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+// Option configures AsMain, Execute and Run.
+type Option func(o *options)
+
+type options struct {
+	itemOrder    ItemOrder
+	resultPolicy *ResultPolicy
+	logResults   bool
+	middlewares  []Middleware
+	// abandonCancelled gives up the processor if it does not stop once the context is done, see processOrAbandon.
+	abandonCancelled bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithItemOrder sets the order of the ResourceList.items in the output. Execute and AsMain default to PreserveOrder,
+// and Run defaults to SortByGVKNN. An unknown order fails the function with an Error result in the output.
+func WithItemOrder(order ItemOrder) Option {
+	return func(o *options) {
+		o.itemOrder = order
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ItemOrder is the order of the ResourceList.items in the function output.
+type ItemOrder string
+
+const (
+	// SortByGVKNN sorts the items by apiVersion, kind, namespace and name. It is the default of ResourceList.ToYAML
+	// and ResourceList.ToJSON.
+	SortByGVKNN ItemOrder = "gvknn"
+	// PreserveOrder keeps the items in the order of the input, or in the order the function put them. It is the
+	// default of Execute and AsMain.
+	PreserveOrder ItemOrder = "preserve"
+	// SortByPath sorts the items by their path annotation and then by their index annotation, which is the order of
+	// the files and of the resources in the files. The items without path annotation are put last.
+	SortByPath ItemOrder = "path"
+	// ApplyOrder sorts the items by kind in the order they should be applied to a cluster, e.g. the Namespaces and
+	// the CustomResourceDefinitions first, and the webhook configurations last. The items of the same kind keep their
+	// order.
+	ApplyOrder ItemOrder = "apply"
+)
+
+// applyOrderFirst and applyOrderLast are the kinds to apply before and after the other kinds.
+var (
+	applyOrderFirst = []string{
+		"Namespace",
+		"ResourceQuota",
+		"StorageClass",
+		"CustomResourceDefinition",
+		"ServiceAccount",
+		"PodSecurityPolicy",
+		"Role",
+		"ClusterRole",
+		"RoleBinding",
+		"ClusterRoleBinding",
+		"ConfigMap",
+		"Secret",
+		"Endpoints",
+		"Service",
+		"LimitRange",
+		"PriorityClass",
+		"PersistentVolume",
+		"PersistentVolumeClaim",
+		"Deployment",
+		"StatefulSet",
+		"CronJob",
+		"PodDisruptionBudget",
+	}
+	applyOrderLast = []string{
+		"MutatingWebhookConfiguration",
+		"ValidatingWebhookConfiguration",
+	}
+)
+
+// SortItems sorts the ResourceList.items in the given order. The empty order is SortByGVKNN.
+func (rl *ResourceList) SortItems(order ItemOrder) error {
+	switch order {
+	case "", SortByGVKNN:
+		rl.Sort()
+	case PreserveOrder:
+	case SortByPath:
+		sort.SliceStable(rl.Items, func(i, j int) bool {
+			pi, pj := rl.Items[i].PathAnnotation(), rl.Items[j].PathAnnotation()
+			if pi != pj {
+				// The items without path go last.
+				return pj == "" || (pi != "" && pi < pj)
+			}
+			return indexOfKubeObject(rl.Items[i]) < indexOfKubeObject(rl.Items[j])
+		})
+	case ApplyOrder:
+		sort.SliceStable(rl.Items, func(i, j int) bool {
+			return applyRank(rl.Items[i].GetKind()) < applyRank(rl.Items[j].GetKind())
+		})
+	default:
+		return fmt.Errorf("unknown item order %q, expect one of %q, %q, %q or %q",
+			order, SortByGVKNN, PreserveOrder, SortByPath, ApplyOrder)
+	}
+	return nil
+}
+
+func applyRank(kind string) int {
+	for i, k := range applyOrderFirst {
+		if k == kind {
+			return i
+		}
+	}
+	for i, k := range applyOrderLast {
+		if k == kind {
+			return len(applyOrderFirst) + 1 + i
+		}
+	}
+	return len(applyOrderFirst)
+}
+
+// indexOfKubeObject returns the index annotation of the object, or the max int if it has none, so that the new
+// objects are put after the existing ones.
+func indexOfKubeObject(obj *KubeObject) int {
+	if index := obj.IndexAnnotation(); index >= 0 {
+		return index
+	}
+	return math.MaxInt
+}
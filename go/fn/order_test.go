@@ -0,0 +1,109 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var orderInput = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: ns
+    annotations:
+      internal.config.kubernetes.io/path: a.yaml
+- apiVersion: example.com/v1
+  kind: Widget
+  metadata:
+    name: generated
+- apiVersion: admissionregistration.k8s.io/v1
+  kind: ValidatingWebhookConfiguration
+  metadata:
+    name: webhook
+    annotations:
+      internal.config.kubernetes.io/path: b.yaml
+      internal.config.kubernetes.io/index: '1'
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+    annotations:
+      internal.config.kubernetes.io/path: b.yaml
+      internal.config.kubernetes.io/index: '0'
+`
+
+func TestItemOrder(t *testing.T) {
+	noop := ResourceListProcessorFunc(func(*ResourceList) (bool, error) { return true, nil })
+	testcases := map[ItemOrder][]string{
+		"":            {"ns", "generated", "webhook", "app"},
+		PreserveOrder: {"ns", "generated", "webhook", "app"},
+		SortByGVKNN:   {"webhook", "app", "generated", "ns"},
+		SortByPath:    {"ns", "app", "webhook", "generated"},
+		ApplyOrder:    {"ns", "app", "generated", "webhook"},
+	}
+	for order, expected := range testcases {
+		var opts []Option
+		if order != "" {
+			opts = append(opts, WithItemOrder(order))
+		}
+		var out bytes.Buffer
+		err := Execute(noop, strings.NewReader(orderInput), &out, opts...)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, itemNames(t, out.Bytes()), order)
+	}
+
+	// An unknown order is reported in the output, which keeps the items in their order.
+	var out bytes.Buffer
+	err := Execute(noop, strings.NewReader(orderInput), &out, WithItemOrder("random"))
+	assert.EqualError(t, err, "error: function failure")
+	assert.Equal(t, []string{"ns", "generated", "webhook", "app"}, itemNames(t, out.Bytes()))
+	rl, err := ParseResourceList(out.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `[error]: unknown item order "random", expect one of "gvknn", "preserve", "path" or "apply"`,
+		rl.Results.String())
+}
+
+func TestRunItemOrder(t *testing.T) {
+	noop := ResourceListProcessorFunc(func(*ResourceList) (bool, error) { return true, nil })
+
+	out, err := Run(noop, []byte(orderInput))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"webhook", "app", "generated", "ns"}, itemNames(t, out))
+
+	out, err = Run(noop, []byte(orderInput), WithItemOrder(PreserveOrder))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns", "generated", "webhook", "app"}, itemNames(t, out))
+
+	out, err = Run(noop, []byte(orderInput), WithItemOrder("random"))
+	assert.EqualError(t, err, "error: function failure")
+	assert.Equal(t, []string{"ns", "generated", "webhook", "app"}, itemNames(t, out))
+}
+
+func itemNames(t *testing.T, out []byte) []string {
+	rl, err := ParseResourceList(out)
+	assert.NoError(t, err)
+	var names []string
+	for _, item := range rl.Items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
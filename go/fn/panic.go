@@ -0,0 +1,110 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicExitCode is the exit code of a function that panicked, to tell it apart from a function failure.
+const PanicExitCode = 2
+
+// PanicError is returned by AsMain, Execute and Run when the ResourceListProcessor panics. The panic is also reported
+// as an Error Result in the ResourceList output.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("function panicked: %v", e.Value)
+}
+
+// ExitCode returns PanicExitCode.
+func (e *PanicError) ExitCode() int {
+	return PanicExitCode
+}
+
+// ExitCode returns the exit code of the error returned by AsMain: 0 if err is nil, PanicExitCode if the function
+// panicked, and 1 otherwise. e.g.
+//
+//	if err := fn.AsMain(runner); err != nil {
+//		os.Exit(fn.ExitCode(err))
+//	}
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitCoder interface{ ExitCode() int }
+	if errors.As(err, &exitCoder) {
+		if code := exitCoder.ExitCode(); code != 0 {
+			return code
+		}
+	}
+	return 1
+}
+
+// itemPanic is the panic value of a function applied to an item, e.g. by ApplyFnBySelector, so that the panic can
+// be reported on the item.
+type itemPanic struct {
+	item  *KubeObject
+	value any
+}
+
+// repanicWithItem is deferred by the functions applied to an item. It adds the item to the panic value.
+func repanicWithItem(item *KubeObject) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if _, ok := v.(itemPanic); !ok {
+		v = itemPanic{item: item, value: v}
+	}
+	panic(v)
+}
+
+// process calls p.Process, and recovers its panic into an Error Result and a PanicError. If p stops because the
+// ResourceList context is done, it is reported as an Error Result too.
+func process(p ResourceListProcessor, rl *ResourceList) (success bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			success, err = false, recoveredPanic(rl, v)
+		}
+	}()
+	success, err = p.Process(rl)
+	if isContextError(err) {
+		rl.Results = append(rl.Results, ErrorResult(err))
+	}
+	return success, err
+}
+
+// recoveredPanic returns the PanicError of the recovered value, and reports it as an Error result on the object
+// being processed, if known.
+func recoveredPanic(rl *ResourceList, v any) *PanicError {
+	panicErr := &PanicError{Value: v, Stack: debug.Stack()}
+	var result *Result
+	if ip, ok := v.(itemPanic); ok {
+		panicErr.Value = ip.value
+		result = ConfigObjectResult(panicErr.Error(), ip.item, Error)
+	} else {
+		result = GeneralResult(panicErr.Error(), Error)
+	}
+	rl.Results = append(rl.Results, result)
+	return panicErr
+}
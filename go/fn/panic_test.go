@@ -0,0 +1,98 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	testcases := []struct {
+		name        string
+		processor   ResourceListProcessor
+		expectedMsg string
+		expectedRef *ResourceRef
+	}{
+		{
+			name: "panic in the processor",
+			processor: ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				panic("boom")
+			}),
+			expectedMsg: "function panicked: boom",
+		},
+		{
+			name: "panic on an item",
+			processor: ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				return true, ApplyFnBySelector(rl, func(*KubeObject) bool { return true }, func(obj *KubeObject) error {
+					var m map[string]string
+					m["name"] = obj.GetName()
+					return nil
+				})
+			}),
+			expectedMsg: "function panicked: assignment to entry in nil map",
+			expectedRef: &ResourceRef{APIVersion: "v1", Kind: "ConfigMap", Name: "example"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Execute(tc.processor, strings.NewReader(runInput), &out)
+			var panicErr *PanicError
+			if assert.True(t, errors.As(err, &panicErr)) {
+				assert.Contains(t, string(panicErr.Stack), "panic_test.go")
+			}
+			assert.Equal(t, PanicExitCode, ExitCode(err))
+
+			rl, parseErr := ParseResourceList(out.Bytes())
+			assert.NoError(t, parseErr)
+			if assert.Len(t, rl.Results, 2) {
+				assert.Equal(t, tc.expectedMsg, rl.Results[1].Message)
+				assert.Equal(t, Error, rl.Results[1].Severity)
+				assert.Equal(t, tc.expectedRef, rl.Results[1].ResourceRef)
+			}
+		})
+	}
+}
+
+func TestRunRecoversPanic(t *testing.T) {
+	out, err := Run(ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		panic(fmt.Errorf("boom"))
+	}), []byte(runInput))
+	assert.EqualError(t, err, "function panicked: boom")
+	assert.Contains(t, string(out), "message: 'function panicked: boom'")
+}
+
+func TestExitCode(t *testing.T) {
+	testcases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "no error", expected: 0},
+		{name: "failure", err: fmt.Errorf("error: function failure"), expected: 1},
+		{name: "results", err: Results{ErrorResult(fmt.Errorf("invalid"))}, expected: 1},
+		{name: "panic", err: &PanicError{Value: "boom"}, expected: PanicExitCode},
+		{name: "wrapped panic", err: fmt.Errorf("wrapped: %w", &PanicError{Value: "boom"}), expected: PanicExitCode},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, ExitCode(tc.err), tc.name)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SuppressAnnotation suppresses the results of a resource when a ResultPolicy is given. Its value is a
+// comma-separated list of result tags, as `key` or `key=value`, or `*` for all the results of the resource. e.g.
+//
+//	metadata:
+//	  annotations:
+//	    fn.kpt.dev/suppress: "rule=min-replicas,experimental"
+const SuppressAnnotation = "fn.kpt.dev/suppress"
+
+// ResultPolicy adjusts the results at the end of the evaluation, so that validators can be adopted incrementally
+// without forking them. The results are first suppressed, then deduplicated, promoted and capped. The function fails
+// if an Error result is left (even if omitted by the cap). A function which fails with its Results as error no longer
+// fails if their Error results are all suppressed, but a function which returns false (e.g. a Runner) still fails.
+type ResultPolicy struct {
+	// WarningsAsErrors promotes the Warning results to Error results.
+	WarningsAsErrors bool
+	// SuppressTags suppresses the results with any of these tags, as `key` or `key=value`.
+	SuppressTags []string
+	// SuppressMessages suppresses the results whose message matches any of these regular expressions.
+	SuppressMessages []*regexp.Regexp
+	// SuppressResources suppresses the results of the resources it selects, e.g. with IsGVK.
+	SuppressResources func(obj *KubeObject) bool
+	// MaxResults caps the number of results of each severity. A severity without cap is not limited.
+	MaxResults map[Severity]int
+	// Deduplicate removes the results which are identical to a previous one.
+	Deduplicate bool
+}
+
+// WithResultPolicy applies the ResultPolicy to the results of the function. The Results returned as error by the
+// function are added to the ResourceList results, so that the policy also applies to them. The results of the
+// previous functions of the pipeline are left as they are.
+func WithResultPolicy(policy ResultPolicy) Option {
+	return func(o *options) {
+		o.resultPolicy = &policy
+	}
+}
+
+// applyResultPolicy applies the policy of the options, if any, to the results added to the ResourceList from the
+// index `from`, i.e. the results of the function and not those of the previous functions of the pipeline, and
+// returns the updated success and error of the function.
+func applyResultPolicy(o *options, rl *ResourceList, from int, success bool, fnErr error) (bool, error) {
+	if o.resultPolicy == nil {
+		return success, fnErr
+	}
+	// The function failed because of its results only if it returned them as error. A function which returned false
+	// (e.g. a Runner) or another error failed for its own reasons.
+	failedWithResults := false
+	if fnErr != nil && addResults(rl, fnErr) {
+		fnErr, failedWithResults = nil, true
+	}
+	if from > len(rl.Results) {
+		from = len(rl.Results)
+	}
+	previous, results := rl.Results[:from:from], rl.Results[from:]
+	failed := results.ExitCode() != 0
+	results, hasErrors := o.resultPolicy.apply(results, rl.Items)
+	rl.Results = append(previous, results...)
+	if hasErrors {
+		return false, fnErr
+	}
+	// The function failed because of its Error results, which are all suppressed.
+	if failedWithResults && failed {
+		success = true
+	}
+	return success, fnErr
+}
+
+// apply returns the results of the policy, and whether Error results are left. The Error results omitted by the cap
+// are left too.
+func (p *ResultPolicy) apply(results Results, items KubeObjects) (Results, bool) {
+	var out Results
+	suppressed := 0
+	seen := map[string]bool{}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if p.suppressed(result, items) {
+			suppressed++
+			continue
+		}
+		if p.Deduplicate {
+			key, err := json.Marshal(result)
+			if err == nil && seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+		}
+		if p.WarningsAsErrors && result.Severity == Warning {
+			promoted := *result
+			promoted.Severity = Error
+			result = &promoted
+		}
+		out = append(out, result)
+	}
+	hasErrors := out.ExitCode() != 0
+
+	counts := map[Severity]int{}
+	omitted := map[Severity]int{}
+	capped := out[:0]
+	for _, result := range out {
+		severity := result.Severity
+		if severity == "" {
+			severity = Info
+		}
+		counts[severity]++
+		if limit, found := p.MaxResults[severity]; found && counts[severity] > limit {
+			omitted[severity]++
+			continue
+		}
+		capped = append(capped, result)
+	}
+	out = capped
+	for _, severity := range []Severity{Error, Warning, Info} {
+		if omitted[severity] > 0 {
+			out = append(out, GeneralResult(fmt.Sprintf("%d more %s results are omitted", omitted[severity], severity), Info))
+		}
+	}
+	if suppressed > 0 {
+		out = append(out, GeneralResult(fmt.Sprintf("%d results are suppressed", suppressed), Info))
+	}
+	return out, hasErrors
+}
+
+func (p *ResultPolicy) suppressed(result *Result, items KubeObjects) bool {
+	for _, tag := range p.SuppressTags {
+		if hasTag(result, tag) {
+			return true
+		}
+	}
+	for _, re := range p.SuppressMessages {
+		if re.MatchString(result.Message) {
+			return true
+		}
+	}
+	if result.ResourceRef == nil {
+		return false
+	}
+	obj := findResultObject(items, result)
+	if obj == nil {
+		return false
+	}
+	if p.SuppressResources != nil && p.SuppressResources(obj) {
+		return true
+	}
+	for _, tag := range strings.Split(obj.GetAnnotation(SuppressAnnotation), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || (tag != "" && hasTag(result, tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTag tells whether the result has the tag `key`, or the tag `key` with the value `value` for `key=value`.
+func hasTag(result *Result, tag string) bool {
+	key, value, hasValue := strings.Cut(tag, "=")
+	actual, found := result.Tags[key]
+	return found && (!hasValue || actual == value)
+}
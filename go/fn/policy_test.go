@@ -0,0 +1,241 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var policyInput = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: legacy
+    annotations:
+      fn.kpt.dev/suppress: "rule=min-replicas, experimental"
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: ignored
+    annotations:
+      fn.kpt.dev/suppress: "*"
+`
+
+// policyValidator returns the same results for every Deployment, and the results as error.
+func policyValidator(rl *ResourceList) (bool, error) {
+	var results Results
+	for _, obj := range rl.Items {
+		results = append(results,
+			resultWithTags(ConfigObjectResult("replicas must be at least 3", obj, Error), "rule", "min-replicas"),
+			resultWithTags(ConfigObjectResult("the image must be pinned", obj, Warning), "rule", "pinned-image"),
+			resultWithTags(ConfigObjectResult("the API is deprecated", obj, Warning), "experimental", ""),
+		)
+	}
+	results.Infof("validated")
+	results.Infof("validated")
+	return true, results
+}
+
+func resultWithTags(result *Result, key, value string) *Result {
+	result.Tags = map[string]string{key: value}
+	return result
+}
+
+func TestResultPolicy(t *testing.T) {
+	testcases := []struct {
+		name            string
+		policy          ResultPolicy
+		expectedResults []string
+		expectedError   string
+	}{
+		{
+			name: "annotations only",
+			expectedResults: []string{
+				"[warning] apps/v1/Deployment/legacy: the image must be pinned",
+				"[error] apps/v1/Deployment/nginx: replicas must be at least 3",
+				"[warning] apps/v1/Deployment/nginx: the image must be pinned",
+				"[warning] apps/v1/Deployment/nginx: the API is deprecated",
+				"[info]: validated",
+				"[info]: validated",
+				"[info]: 5 results are suppressed",
+			},
+			expectedError: "error: function failure",
+		},
+		{
+			name:   "suppress by tag",
+			policy: ResultPolicy{SuppressTags: []string{"rule=min-replicas"}},
+			expectedResults: []string{
+				"[warning] apps/v1/Deployment/legacy: the image must be pinned",
+				"[warning] apps/v1/Deployment/nginx: the image must be pinned",
+				"[warning] apps/v1/Deployment/nginx: the API is deprecated",
+				"[info]: validated",
+				"[info]: validated",
+				"[info]: 6 results are suppressed",
+			},
+		},
+		{
+			name: "suppress by message and resource",
+			policy: ResultPolicy{
+				SuppressMessages:  []*regexp.Regexp{regexp.MustCompile("^the image")},
+				SuppressResources: func(obj *KubeObject) bool { return obj.GetName() == "nginx" },
+			},
+			expectedResults: []string{
+				"[info]: validated",
+				"[info]: validated",
+				"[info]: 9 results are suppressed",
+			},
+		},
+		{
+			name:   "warnings as errors",
+			policy: ResultPolicy{WarningsAsErrors: true, SuppressTags: []string{"rule=min-replicas"}},
+			expectedResults: []string{
+				"[error] apps/v1/Deployment/legacy: the image must be pinned",
+				"[error] apps/v1/Deployment/nginx: the image must be pinned",
+				"[error] apps/v1/Deployment/nginx: the API is deprecated",
+				"[info]: validated",
+				"[info]: validated",
+				"[info]: 6 results are suppressed",
+			},
+			expectedError: "error: function failure",
+		},
+		{
+			name:   "deduplicate and cap",
+			policy: ResultPolicy{Deduplicate: true, MaxResults: map[Severity]int{Warning: 1, Error: 0}},
+			expectedResults: []string{
+				"[warning] apps/v1/Deployment/legacy: the image must be pinned",
+				"[info]: validated",
+				"[info]: 1 more error results are omitted",
+				"[info]: 2 more warning results are omitted",
+				"[info]: 5 results are suppressed",
+			},
+			expectedError: "error: function failure",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Run(ResourceListProcessorFunc(policyValidator), []byte(policyInput), WithResultPolicy(tc.policy),
+				WithItemOrder(PreserveOrder))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			rl, err := ParseResourceList(out)
+			assert.NoError(t, err)
+			var results []string
+			for _, result := range rl.Results {
+				results = append(results, result.String())
+			}
+			assert.Equal(t, tc.expectedResults, results)
+		})
+	}
+}
+
+func TestResultPolicySuppressedFailure(t *testing.T) {
+	testcases := []struct {
+		name          string
+		processor     ResourceListProcessor
+		expectedError string
+	}{
+		{
+			name:      "results as error",
+			processor: ResourceListProcessorFunc(policyValidator),
+		},
+		{
+			name: "runner returns true",
+			processor: WithContext(context.Background(), runnerFunc(func(ctx *Context, results *Results) bool {
+				*results = append(*results, resultWithTags(&Result{Message: "suppressed", Severity: Error}, "rule", "min-replicas"))
+				return true
+			})),
+		},
+		{
+			name: "runner returns false",
+			processor: WithContext(context.Background(), runnerFunc(func(ctx *Context, results *Results) bool {
+				*results = append(*results, resultWithTags(&Result{Message: "suppressed", Severity: Error}, "rule", "min-replicas"))
+				return false
+			})),
+			expectedError: "error: function failure",
+		},
+		{
+			name: "other error",
+			processor: ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+				rl.Results = append(rl.Results, resultWithTags(&Result{Message: "suppressed", Severity: Error}, "rule", "min-replicas"))
+				return false, fmt.Errorf("failed to validate")
+			}),
+			expectedError: "failed to validate",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Run(tc.processor, []byte(policyInput), WithResultPolicy(ResultPolicy{
+				SuppressTags: []string{"rule=min-replicas", "rule=pinned-image", "experimental"},
+			}))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNoResultPolicy(t *testing.T) {
+	_, err := Run(ResourceListProcessorFunc(policyValidator), []byte(policyInput))
+	_, isResults := err.(Results)
+	assert.True(t, isResults)
+}
+
+func TestResultPolicyInputResults(t *testing.T) {
+	input := policyInput + `results:
+- message: the image must be pinned
+  severity: warning
+- message: from a previous function
+  severity: error
+`
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Warningf("the image must be pinned")
+		rl.Results.Warningf("the API is deprecated")
+		return true, nil
+	})
+	out, err := Run(p, []byte(input), WithResultPolicy(ResultPolicy{
+		SuppressMessages: []*regexp.Regexp{regexp.MustCompile("^the image")},
+		WarningsAsErrors: true,
+	}))
+	assert.EqualError(t, err, "error: function failure")
+	rl, err := ParseResourceList(out)
+	assert.NoError(t, err)
+	var results []string
+	for _, result := range rl.Results {
+		results = append(results, result.String())
+	}
+	// The results of the previous functions are left as they are.
+	assert.Equal(t, []string{
+		"[warning]: the image must be pinned",
+		"[error]: from a previous function",
+		"[error]: the API is deprecated",
+		"[info]: 1 results are suppressed",
+	}, results)
+}
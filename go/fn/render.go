@@ -0,0 +1,255 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+	colorFaint  = "\x1b[2m"
+)
+
+// isTerminal tells whether the file is a terminal. It is a variable for the tests.
+var isTerminal = func(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Render formats the results for a terminal. The results are grouped by file and by resource, and followed by a
+// count of the results per severity. If a result has a Field.Path and its resource is in `items`, the line of the
+// field in the resource YAML is shown, with the field marked. The line numbers count from the first line of the
+// resource, not of its file, since the function does not see the file. With `color`, the severities are colorized with ANSI
+// escape codes.
+func (r Results) Render(items KubeObjects, color bool) string {
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + colorReset
+	}
+
+	// Group the results by file and resource, in the order of their first result.
+	type group struct {
+		resource string
+		results  Results
+	}
+	var files []string
+	groups := map[string][]*group{}
+	counts := map[Severity]int{}
+	for _, result := range r {
+		if result == nil {
+			continue
+		}
+		file, resource := "", ""
+		if result.File != nil {
+			file = result.File.Path
+		}
+		if result.ResourceRef != nil {
+			resource = resourceRefString(result.ResourceRef)
+		}
+		if _, found := groups[file]; !found {
+			files = append(files, file)
+		}
+		var g *group
+		for _, existing := range groups[file] {
+			if existing.resource == resource {
+				g = existing
+			}
+		}
+		if g == nil {
+			g = &group{resource: resource}
+			groups[file] = append(groups[file], g)
+		}
+		g.results = append(g.results, result)
+		counts[severityOf(result)]++
+	}
+
+	var out strings.Builder
+	for _, file := range files {
+		indent := ""
+		if file != "" {
+			out.WriteString(paint(colorBold, file) + "\n")
+			indent = "  "
+		}
+		for _, g := range groups[file] {
+			resultIndent := indent
+			if g.resource != "" {
+				out.WriteString(indent + paint(colorBold, g.resource) + "\n")
+				resultIndent += "  "
+			}
+			for _, result := range g.results {
+				severity := severityOf(result)
+				out.WriteString(resultIndent + paint(severityColor(severity), string(severity)))
+				if result.Field != nil && result.Field.Path != "" {
+					out.WriteString(" " + result.Field.Path)
+				}
+				out.WriteString(": " + result.Message + "\n")
+				out.WriteString(renderSnippet(result, items, resultIndent+"  ", paint))
+			}
+		}
+	}
+
+	var summary []string
+	for _, severity := range []Severity{Error, Warning, Info} {
+		count := fmt.Sprintf("%d %s", counts[severity], severity)
+		if counts[severity] != 1 && severity != Info {
+			count += "s"
+		}
+		summary = append(summary, paint(severityColor(severity), count))
+	}
+	out.WriteString(strings.Join(summary, ", ") + "\n")
+	return out.String()
+}
+
+// renderSnippet returns the line of the result field in the YAML of its resource, with the line before and after
+// it, and a marker under the field. The field is located by its path in the resource as it is now, since the
+// function may have changed the resource after the result was made.
+func renderSnippet(result *Result, items KubeObjects, indent string, paint func(code, s string) string) string {
+	if result.Field == nil || result.Field.Path == "" || result.ResourceRef == nil {
+		return ""
+	}
+	obj := findResultObject(items, result)
+	if obj == nil {
+		return ""
+	}
+	text := obj.String()
+	node, err := yaml.Parse(text)
+	if err != nil {
+		return ""
+	}
+	position := fieldPosition(node.YNode(), result.Field.Path)
+	if position == nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	line := position.Line - 1
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+	color := severityColor(severityOf(result))
+	width := len(fmt.Sprint(min(line+2, len(lines))))
+	var out strings.Builder
+	out.WriteString(indent + paint(colorFaint, fmt.Sprintf("--> resource line %d", line+1)) + "\n")
+	for i := max(line-1, 0); i <= min(line+1, len(lines)-1); i++ {
+		number := fmt.Sprintf("%*d |", width, i+1)
+		if i != line {
+			out.WriteString(indent + paint(colorFaint, number+" "+lines[i]) + "\n")
+			continue
+		}
+		out.WriteString(indent + number + " " + paint(color, lines[i]) + "\n")
+		marker := strings.Repeat(" ", width) + " | " + strings.Repeat(" ", position.Column-1)
+		out.WriteString(indent + marker + paint(color, "^") + "\n")
+	}
+	return out.String()
+}
+
+// fieldPosition returns the key node of the field path (see Fixer) in the node, or the node of its deepest existing
+// parent if the field does not exist. It returns nil if the path is invalid.
+func fieldPosition(node *yaml.Node, path string) *yaml.Node {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil
+	}
+	position := node
+	for _, segment := range segments {
+		if segment.key != "" {
+			if node.Kind != yaml.MappingNode {
+				break
+			}
+			i := fieldIndex(node, segment.key)
+			if i < 0 {
+				break
+			}
+			position, node = node.Content[i], node.Content[i+1]
+			continue
+		}
+		if node.Kind != yaml.SequenceNode {
+			break
+		}
+		item := segment.find(node)
+		if item == nil {
+			break
+		}
+		position, node = item, item
+	}
+	return position
+}
+
+func severityOf(result *Result) Severity {
+	if result.Severity == "" {
+		return Info
+	}
+	return result.Severity
+}
+
+func severityColor(severity Severity) string {
+	switch severity {
+	case Error:
+		return colorRed
+	case Warning:
+		return colorYellow
+	default:
+		return colorCyan
+	}
+}
+
+// terminalColor tells whether the results rendered to the terminal are colorized, i.e. unless the NO_COLOR
+// environment variable is set (see https://no-color.org).
+func terminalColor() bool {
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// logResults prints the results to STDERR, rendered for the terminal if STDERR is one.
+func logResults(results Results, items KubeObjects) {
+	if len(results) == 0 {
+		return
+	}
+	if isTerminal(os.Stderr) {
+		Logf("%s", results.Render(items, terminalColor()))
+		return
+	}
+	for _, result := range results {
+		Log(result.String())
+	}
+}
+
+// terminalReadWriter renders the results of the ResourceList to STDERR, which is a terminal, when writing it. The
+// results are colorized unless NO_COLOR is set.
+type terminalReadWriter struct {
+	resourceListReadWriter
+	// items are the items of the ResourceList written, to render the results returned as error by the function.
+	items KubeObjects
+}
+
+func (rw *terminalReadWriter) Write(rl *ResourceList) error {
+	if err := rw.resourceListReadWriter.Write(rl); err != nil {
+		return err
+	}
+	rw.items = rl.Items
+	if len(rl.Results) > 0 {
+		Logf("%s", rl.Results.Render(rl.Items, terminalColor()))
+	}
+	return nil
+}
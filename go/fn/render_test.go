@@ -0,0 +1,142 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestRender(t *testing.T) {
+	rl, err := ParseResourceList([]byte(locationInput))
+	assert.NoError(t, err)
+	deployment := rl.Items[1]
+	container := deployment.GetMap("spec").GetMap("template").GetMap("spec").GetSlice("containers")[0]
+
+	var results Results
+	results.ErrorAt(deployment.GetMap("spec"), "replicas", "too few replicas")
+	results.WarningAt(container, "image", "latest tag")
+	results = append(results, ConfigObjectResult("no owner", rl.Items[0], Info), GeneralResult("done", ""))
+	expected := `deploy.yaml
+  apps/v1/Deployment/default/nginx
+    error spec.replicas: too few replicas
+      --> resource line 10
+       9 | spec:
+      10 |   replicas: 1
+         |   ^
+      11 |   template:
+    warning spec.template.spec.containers[0].image: latest tag
+      --> resource line 15
+      14 |       - name: nginx
+      15 |         image: nginx:latest
+         |         ^
+v1/ConfigMap/example
+  info: no owner
+info: done
+1 error, 1 warning, 2 info
+`
+	assert.Equal(t, expected, results.Render(rl.Items, false))
+
+	colored := results.Render(rl.Items, true)
+	assert.Contains(t, colored, colorRed+"error"+colorReset+" spec.replicas: too few replicas")
+	assert.Contains(t, colored, colorYellow+"1 warning"+colorReset)
+}
+
+func TestRenderChangedResource(t *testing.T) {
+	rl, err := ParseResourceList([]byte(locationInput))
+	assert.NoError(t, err)
+	deployment := rl.Items[1]
+
+	var results Results
+	results.ErrorAt(deployment.GetMap("spec"), "replicas", "too few replicas")
+	// The snippet follows the resource as written, not as read.
+	_, err = deployment.RemoveNestedField("metadata", "namespace")
+	assert.NoError(t, err)
+	assert.NoError(t, deployment.SetNestedField(map[string]any{"app": "nginx"}, "spec", "selector", "matchLabels"))
+	results[0].ResourceRef.Namespace = ""
+
+	assert.Equal(t, `deploy.yaml
+  apps/v1/Deployment/nginx
+    error spec.replicas: too few replicas
+      --> resource line 9
+       8 | spec:
+       9 |   replicas: 1
+         |   ^
+      10 |   template:
+1 error, 0 warnings, 0 info
+`, results.Render(rl.Items, false))
+}
+
+func TestFieldPosition(t *testing.T) {
+	rl, err := ParseResourceList([]byte(locationInput))
+	assert.NoError(t, err)
+	// The positions are those of the resource text, as rendered.
+	resource, err := yaml.Parse(rl.Items[1].String())
+	assert.NoError(t, err)
+	node := resource.YNode()
+
+	testcases := []struct {
+		path   string
+		line   int
+		column int
+	}{
+		{path: "spec.replicas", line: 10, column: 3},
+		{path: "spec.template.spec.containers[name=nginx].image", line: 15, column: 9},
+		{path: "spec.template.spec.containers[0]", line: 14, column: 9},
+		// The deepest existing parent.
+		{path: "spec.template.spec.containers[1].image", line: 13, column: 7},
+		{path: "spec.minReplicas", line: 9, column: 1},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.path, func(t *testing.T) {
+			position := fieldPosition(node, tc.path)
+			if assert.NotNil(t, position) {
+				assert.Equal(t, tc.line, position.Line)
+				assert.Equal(t, tc.column, position.Column)
+			}
+		})
+	}
+	assert.Nil(t, fieldPosition(node, "spec.containers[0"))
+}
+
+func TestTerminalReadWriterNoColor(t *testing.T) {
+	terminal := isTerminal
+	isTerminal = func(*os.File) bool { return true }
+	defer func() { isTerminal = terminal }()
+	rl := &ResourceList{Results: Results{GeneralResult("too few replicas", Error)}}
+
+	testcases := map[string]struct {
+		noColor  string
+		expected string
+	}{
+		"color":    {expected: colorRed + "error" + colorReset + ": too few replicas\n"},
+		"no color": {noColor: "1", expected: "error: too few replicas\n"},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			rw := &terminalReadWriter{resourceListReadWriter: newByteReadWriter(strings.NewReader(""), &bytes.Buffer{})}
+			stderr := captureStderr(t, func() {
+				assert.NoError(t, rw.Write(rl))
+			})
+			assert.True(t, strings.HasPrefix(stderr, tc.expected), stderr)
+		})
+	}
+}
@@ -4,9 +4,12 @@
 package fn
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 
 	"github.com/kptdev/krm-functions-sdk/go/fn/internal"
 	pkgerrors "github.com/pkg/errors"
@@ -57,6 +60,39 @@ type ResourceList struct {
 	// Validating functions can optionally use this field to communicate structured
 	// validation error data to downstream functions.
 	Results Results `yaml:"results,omitempty" json:"results,omitempty"`
+
+	// ItemOrder is the order of the Items in the ToYAML and ToJSON output. It defaults to SortByGVKNN.
+	ItemOrder ItemOrder `yaml:"-" json:"-"`
+
+	// ctx is the context of the ResourceList evaluation, e.g. with the deadline of a Serve request.
+	ctx context.Context
+	// logResults mirrors the Warn and Error records of the Logger into the Results. See WithLogResults.
+	logResults bool
+	// logMu serializes the appends of the Logger records to the Results when logResults is set, since the loggers may
+	// be used concurrently, e.g. by the goroutines of a Runner. It does not guard the other changes of the Results. It
+	// is a pointer, so that the ResourceList can be copied, and is shared with the views of Scope.
+	logMu *sync.Mutex
+	// span is the current span of the trace of the evaluation, or nil if it is not traced. See TraceFileEnv.
+	span *span
+	// copyRunner makes the Runners (or Generators) decode the functionConfig to a copy of themselves, so that the
+	// concurrent evaluations do not share it. See Concurrent.
+	copyRunner bool
+	// input is the raw ResourceList input it was parsed from, or nil if it was not parsed. Its size is in the trace
+	// metrics, and AsMain parses it again to output the input of an abandoned evaluation.
+	input []byte
+}
+
+// Context returns the context of the ResourceList evaluation. It is never nil.
+func (rl *ResourceList) Context() context.Context {
+	if rl.ctx == nil {
+		return context.Background()
+	}
+	return rl.ctx
+}
+
+// SetContext sets the context of the ResourceList evaluation. The processors should stop when it is done.
+func (rl *ResourceList) SetContext(ctx context.Context) {
+	rl.ctx = ctx
 }
 
 // CheckResourceDuplication checks the GVKNN of resourceList.items to make sure they are unique. It returns errors if
@@ -75,9 +111,9 @@ func CheckResourceDuplication(rl *ResourceList) error {
 }
 
 // ParseResourceList parses a ResourceList from the input byte array. This function can be used to parse either KRM fn input
-// or KRM fn output
+// or KRM fn output, in either yaml or json format.
 func ParseResourceList(in []byte) (*ResourceList, error) {
-	rl := &ResourceList{}
+	rl := &ResourceList{input: in}
 	rlObj, err := ParseKubeObject(in)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse input bytes: %w", err)
@@ -183,10 +219,11 @@ func (rl *ResourceList) toYNode() (*yaml.Node, error) {
 	return reMap.Node(), nil
 }
 
-// ToYAML converts the ResourceList to yaml.
+// ToYAML converts the ResourceList to yaml. The items are sorted in the ItemOrder first.
 func (rl *ResourceList) ToYAML() ([]byte, error) {
-	// Sort the resources first.
-	rl.Sort()
+	if err := rl.SortItems(rl.ItemOrder); err != nil {
+		return nil, err
+	}
 	ynode, err := rl.toYNode()
 	if err != nil {
 		return nil, err
@@ -195,6 +232,26 @@ func (rl *ResourceList) ToYAML() ([]byte, error) {
 	return doc.ToYAML()
 }
 
+// ToJSON converts the ResourceList to json. The items are sorted the same way as ToYAML does, and their fields keep
+// their order.
+func (rl *ResourceList) ToJSON() ([]byte, error) {
+	if err := rl.SortItems(rl.ItemOrder); err != nil {
+		return nil, err
+	}
+	ynode, err := rl.toYNode()
+	if err != nil {
+		return nil, err
+	}
+	doc := internal.NewDoc([]*yaml.Node{ynode}...)
+	return doc.ToJSON()
+}
+
+// isJSON tells whether the serialized ResourceList is in json rather than yaml format.
+func isJSON(in []byte) bool {
+	trimmed := bytes.TrimLeft(in, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
 // Sort sorts the ResourceList.items by apiVersion, kind, namespace and name.
 func (rl *ResourceList) Sort() {
 	sort.Sort(rl.Items)
@@ -279,12 +336,18 @@ func (p ResourceListProcessorFunc) Process(rl *ResourceList) (bool, error) {
 	return p(rl)
 }
 
-// Chain chains a list of ResourceListProcessor as a single ResourceListProcessor.
+// Chain chains a list of ResourceListProcessor as a single ResourceListProcessor. It stops with the cause of the
+// ResourceList context when the context is done.
 func Chain(processors ...ResourceListProcessor) ResourceListProcessor {
-	return ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
-		success := true
-		for _, processor := range processors {
-			s, err := processor.Process(rl)
+	return ResourceListProcessorFunc(func(rl *ResourceList) (success bool, err error) {
+		span := startSpan(rl, "Chain")
+		defer func() { endSpan(rl, span, err) }()
+		success = true
+		for i, processor := range processors {
+			if err := contextError(rl); err != nil {
+				return false, err
+			}
+			s, err := traceStep(rl, "Chain", i, processor, processor.Process)
 			if !s {
 				success = false
 			}
@@ -297,12 +360,17 @@ func Chain(processors ...ResourceListProcessor) ResourceListProcessor {
 }
 
 // ChainFunctions chains a list of ResourceListProcessorFunc as a single
-// ResourceListProcessorFunc.
+// ResourceListProcessorFunc. It stops the same way as Chain does.
 func ChainFunctions(functions ...ResourceListProcessorFunc) ResourceListProcessorFunc {
-	return func(rl *ResourceList) (bool, error) {
-		success := true
-		for _, fn := range functions {
-			s, err := fn(rl)
+	return func(rl *ResourceList) (success bool, err error) {
+		span := startSpan(rl, "ChainFunctions")
+		defer func() { endSpan(rl, span, err) }()
+		success = true
+		for i, fn := range functions {
+			if err := contextError(rl); err != nil {
+				return false, err
+			}
+			s, err := traceStep(rl, "ChainFunctions", i, fn, fn)
 			if !s {
 				success = false
 			}
@@ -315,14 +383,28 @@ func ChainFunctions(functions ...ResourceListProcessorFunc) ResourceListProcesso
 }
 
 // ApplyFnBySelector iterates through every object in ResourceList.items, and if
-// it satisfies the selector, fn will be applied on it.
-func ApplyFnBySelector(rl *ResourceList, selector func(obj *KubeObject) bool, fn func(obj *KubeObject) error) error {
+// it satisfies the selector, fn will be applied on it. The errors of fn are added to
+// the ResourceList results, and returned as Results. It stops with the cause of
+// the ResourceList context when the context is done. See Scope to process a
+// filtered view of the items instead.
+func ApplyFnBySelector(rl *ResourceList, selector func(obj *KubeObject) bool, fn func(obj *KubeObject) error) (err error) {
+	span := startSpan(rl, "ApplyFnBySelector")
+	selected := 0
+	defer func() {
+		span.set("selected", selected)
+		span.count(metricItemsProcessed, selected)
+		endSpan(rl, span, err)
+	}()
 	var results Results
 	for i, obj := range rl.Items {
+		if err := contextError(rl); err != nil {
+			return err
+		}
 		if !selector(obj) {
 			continue
 		}
-		err := fn(rl.Items[i])
+		selected++
+		err := applyToItem(fn, rl.Items[i])
 		if err == nil {
 			continue
 		}
@@ -336,8 +418,15 @@ func ApplyFnBySelector(rl *ResourceList, selector func(obj *KubeObject) bool, fn
 		}
 	}
 	if len(results) > 0 {
-		rl.Results = results
+		rl.Results = append(rl.Results, results...)
 		return results
 	}
 	return nil
 }
+
+// applyToItem applies fn on the item. If fn panics, the item is added to the panic value, so that the panic is
+// reported on the item.
+func applyToItem(fn func(obj *KubeObject) error, item *KubeObject) error {
+	defer repanicWithItem(item)
+	return fn(item)
+}
@@ -15,12 +15,22 @@
 package fn
 
 import (
+	"bytes"
+	"io"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn/internal"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
-// Read decodes input bytes into a ResourceList
+// Read decodes input bytes (in yaml or json format) into a ResourceList
 func (rw *byteReadWriter) Read() (*ResourceList, error) {
+	in, err := io.ReadAll(rw.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	rw.json = isJSON(in)
+	rw.Reader = bytes.NewReader(in)
 	nodes, err := rw.ByteReadWriter.Read()
 	if err != nil {
 		return nil, err
@@ -33,17 +43,30 @@ func (rw *byteReadWriter) Read() (*ResourceList, error) {
 		}
 		items = append(items, obj)
 	}
-	obj, err := ParseKubeObject([]byte(rw.FunctionConfig.MustString()))
-	if err != nil {
-		return nil, err
+	// FunctionConfig can be empty, e.g. `kubeconform` fn does not require a FunctionConfig.
+	obj := NewEmptyKubeObject()
+	if rw.FunctionConfig != nil {
+		obj, err = ParseKubeObject([]byte(rw.FunctionConfig.MustString()))
+		if err != nil {
+			return nil, err
+		}
+	}
+	// If running in a pipeline, the ResourceList may already have results from previous function runs.
+	var results Results
+	if rw.Results != nil {
+		if err := rw.Results.YNode().Decode(&results); err != nil {
+			return nil, errors.WrapPrefixf(err, "failed to decode results")
+		}
 	}
 	return &ResourceList{
 		Items:          items,
 		FunctionConfig: obj,
+		Results:        results,
+		input:          in,
 	}, nil
 }
 
-// Write writes a ResourceList into bytes
+// Write writes a ResourceList into bytes, in the format of the input
 func (rw *byteReadWriter) Write(rl *ResourceList) error {
 	if len(rl.Results) > 0 {
 		b, err := yaml.Marshal(rl.Results)
@@ -64,5 +87,25 @@ func (rw *byteReadWriter) Write(rl *ResourceList) error {
 		}
 		nodes = append(nodes, node)
 	}
-	return rw.ByteReadWriter.Write(nodes)
+	if !rw.json {
+		return rw.ByteReadWriter.Write(nodes)
+	}
+	// Write the yaml output first, so that the annotations are handled the same way.
+	out := rw.Writer
+	var buf bytes.Buffer
+	rw.Writer = &buf
+	defer func() { rw.Writer = out }()
+	if err := rw.ByteReadWriter.Write(nodes); err != nil {
+		return err
+	}
+	doc, err := internal.ParseDoc(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	j, err := doc.ToJSON()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(j)
+	return errors.Wrap(err)
 }
@@ -15,6 +15,8 @@
 package fn
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -78,3 +80,68 @@ results:
 		t.Fatalf("unexpected diff: %v", cmp.Diff(expected, rl.Results))
 	}
 }
+
+var jsonResourceList = `{
+  "apiVersion": "config.kubernetes.io/v1",
+  "kind": "ResourceList",
+  "items": [
+    {
+      "kind": "Deployment",
+      "apiVersion": "apps/v1",
+      "metadata": {
+        "name": "example",
+        "annotations": {
+          "internal.config.kubernetes.io/path": "deploy.yaml"
+        }
+      },
+      "spec": {
+        "replicas": 3,
+        "paused": false,
+        "strategy": null,
+        "selector": {
+          "matchLabels": {
+            "version": "1.0"
+          }
+        }
+      }
+    }
+  ]
+}
+`
+
+func TestResourceListJSON(t *testing.T) {
+	noop := ResourceListProcessorFunc(func(*ResourceList) (bool, error) { return true, nil })
+
+	out, err := Run(noop, []byte(jsonResourceList))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if diff := cmp.Diff(jsonResourceList, string(out)); diff != "" {
+		t.Errorf("Run json output (-want +got): %s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err = Execute(noop, strings.NewReader(jsonResourceList), &buf); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if diff := cmp.Diff(jsonResourceList, buf.String()); diff != "" {
+		t.Errorf("Execute json output (-want +got): %s", diff)
+	}
+
+	// The yaml input is written in yaml, with its comments.
+	yamlInput := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: example # the namespace
+`
+	buf.Reset()
+	if err = Execute(noop, strings.NewReader(yamlInput), &buf); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if diff := cmp.Diff(yamlInput, buf.String()); diff != "" {
+		t.Errorf("Execute yaml output (-want +got): %s", diff)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // Severity indicates the severity of the Result
@@ -58,6 +60,10 @@ type Result struct {
 	// File references a file containing the resource this result refers to
 	File *File `yaml:"file,omitempty" json:"file,omitempty"`
 
+	// ResourcePosition is the position of the field in the resource this result refers to. It is not part of the
+	// results of the KRM functions specification, so the tools which only know the specification ignore it.
+	ResourcePosition *ResourcePosition `yaml:"resourcePosition,omitempty" json:"resourcePosition,omitempty"`
+
 	// Tags is an unstructured key value map stored with a result that may be set
 	// by external tools to store and retrieve arbitrary metadata
 	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
@@ -69,21 +75,9 @@ func (i Result) Error() string {
 
 // String provides a human-readable message for the result item
 func (i Result) String() string {
-	identifier := i.ResourceRef
-	var idStringList []string
-	if identifier != nil {
-		if identifier.APIVersion != "" {
-			idStringList = append(idStringList, identifier.APIVersion)
-		}
-		if identifier.Kind != "" {
-			idStringList = append(idStringList, identifier.Kind)
-		}
-		if identifier.Namespace != "" {
-			idStringList = append(idStringList, identifier.Namespace)
-		}
-		if identifier.Name != "" {
-			idStringList = append(idStringList, identifier.Name)
-		}
+	var identifier string
+	if i.ResourceRef != nil {
+		identifier = resourceRefString(i.ResourceRef)
 	}
 	formatString := "[%s]"
 	severity := i.Severity
@@ -92,9 +86,9 @@ func (i Result) String() string {
 		severity = Info
 	}
 	list := []interface{}{severity}
-	if len(idStringList) > 0 {
+	if identifier != "" {
 		formatString += " %s"
-		list = append(list, strings.Join(idStringList, "/"))
+		list = append(list, identifier)
 	}
 	if i.Field != nil {
 		formatString += " %s"
@@ -105,6 +99,17 @@ func (i Result) String() string {
 	return fmt.Sprintf(formatString, list...)
 }
 
+// resourceRefString returns the `apiVersion/kind/namespace/name` of the resource.
+func resourceRefString(ref *ResourceRef) string {
+	var parts []string
+	for _, part := range []string{ref.APIVersion, ref.Kind, ref.Namespace, ref.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
 // File references a file containing a resource
 type File struct {
 	// Path is relative path to the file containing the resource.
@@ -116,6 +121,17 @@ type File struct {
 	Index int `yaml:"index,omitempty" json:"index,omitempty"`
 }
 
+// ResourcePosition is the position of a field in its resource. It is relative to the resource, not to its file: the
+// function only sees the resource, not the comments, the document separators or the other resources before it in
+// the file, so the line in the file is not known.
+type ResourcePosition struct {
+	// Line is the line of the field, counting from 1 at the first field of the resource (e.g. `apiVersion`).
+	Line int `yaml:"line,omitempty" json:"line,omitempty"`
+
+	// Column is the column of the field, counting from 1 at the column of the first field of the resource.
+	Column int `yaml:"column,omitempty" json:"column,omitempty"`
+}
+
 // Field references a field in a resource
 type Field struct {
 	// Path is the field path. This field is required.
@@ -173,6 +189,25 @@ func (r *Results) WarningE(err error) {
 	*r = append(*r, warnResult)
 }
 
+// ErrorAt writes an Error level `result` about the `field` of the SubObject (or about the SubObject itself if `field`
+// is empty) to the results slice. See SubObjectResult.
+// e.g.
+//
+//	results.ErrorAt(container, "image", "the image must be pinned by digest")
+func (r *Results) ErrorAt(o *SubObject, field, msg string) {
+	*r = append(*r, SubObjectResult(msg, o, field, Error))
+}
+
+// WarningAt writes a Warning level `result` about the `field` of the SubObject. See ErrorAt.
+func (r *Results) WarningAt(o *SubObject, field, msg string) {
+	*r = append(*r, SubObjectResult(msg, o, field, Warning))
+}
+
+// InfoAt writes an Info level `result` about the `field` of the SubObject. See ErrorAt.
+func (r *Results) InfoAt(o *SubObject, field, msg string) {
+	*r = append(*r, SubObjectResult(msg, o, field, Info))
+}
+
 func (r *Results) String() string {
 	var results []string
 	for _, result := range *r {
@@ -302,3 +337,35 @@ func ConfigObjectResult(msg string, obj *KubeObject, severity Severity) *Result
 		},
 	}
 }
+
+// SubObjectResult returns the Result about the `field` of the SubObject, or about the SubObject itself if `field` is
+// empty. The ResourceRef and the File path and index are taken from the KubeObject of the SubObject, the Field path
+// and scalar value from the field, and the ResourcePosition from the position of the field in the resource. If the
+// field does not exist, e.g. a missing required field, the Result points at the SubObject.
+func SubObjectResult(msg string, o *SubObject, field string, severity Severity) *Result {
+	result := ConfigObjectResult(msg, o.rootObject(), severity)
+	path := strings.TrimPrefix(o.fieldpath, ".")
+	node := o.obj.Node()
+	if field != "" {
+		if path != "" {
+			path += "."
+		}
+		path += field
+		if keyNode, valueNode, found := o.obj.Field(field); found {
+			node = keyNode
+			if valueNode.Kind == yaml.ScalarNode {
+				var value any
+				if err := valueNode.Decode(&value); err == nil {
+					result.Field = &Field{Path: path, CurrentValue: value}
+				}
+			}
+		}
+	}
+	if result.Field == nil && path != "" {
+		result.Field = &Field{Path: path}
+	}
+	if root := o.rootObject().obj.Node(); node != nil && node.Line > 0 && root.Line > 0 {
+		result.ResourcePosition = &ResourcePosition{Line: node.Line - root.Line + 1, Column: node.Column - root.Column + 1}
+	}
+	return result
+}
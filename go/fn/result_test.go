@@ -0,0 +1,134 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var locationInput = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+    namespace: default
+    annotations:
+      internal.config.kubernetes.io/path: deploy.yaml
+      internal.config.kubernetes.io/index: '1'
+  spec:
+    replicas: 1
+    template:
+      spec:
+        containers:
+        - name: nginx
+          image: nginx:latest
+`
+
+func TestSubObjectResult(t *testing.T) {
+	rl, err := ParseResourceList([]byte(locationInput))
+	assert.NoError(t, err)
+	deployment := rl.Items[1]
+	container := deployment.GetMap("spec").GetMap("template").GetMap("spec").GetSlice("containers")[0]
+	ref := &ResourceRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", Namespace: "default"}
+
+	testcases := []struct {
+		name     string
+		object   *SubObject
+		field    string
+		expected *Result
+	}{
+		{
+			name:   "resource",
+			object: &deployment.SubObject,
+			expected: &Result{
+				Message: "msg", Severity: Error, ResourceRef: ref,
+				File:             &File{Path: "deploy.yaml", Index: 1},
+				ResourcePosition: &ResourcePosition{Line: 1, Column: 1},
+			},
+		},
+		{
+			name:   "scalar field",
+			object: deployment.GetMap("spec"),
+			field:  "replicas",
+			expected: &Result{
+				Message: "msg", Severity: Error, ResourceRef: ref,
+				Field:            &Field{Path: "spec.replicas", CurrentValue: 1},
+				File:             &File{Path: "deploy.yaml", Index: 1},
+				ResourcePosition: &ResourcePosition{Line: 10, Column: 3},
+			},
+		},
+		{
+			name:   "list item field",
+			object: container,
+			field:  "image",
+			expected: &Result{
+				Message: "msg", Severity: Error, ResourceRef: ref,
+				Field:            &Field{Path: "spec.template.spec.containers[0].image", CurrentValue: "nginx:latest"},
+				File:             &File{Path: "deploy.yaml", Index: 1},
+				ResourcePosition: &ResourcePosition{Line: 15, Column: 9},
+			},
+		},
+		{
+			name:   "missing field points at the parent",
+			object: container,
+			field:  "resources",
+			expected: &Result{
+				Message: "msg", Severity: Error, ResourceRef: ref,
+				Field:            &Field{Path: "spec.template.spec.containers[0].resources"},
+				File:             &File{Path: "deploy.yaml", Index: 1},
+				ResourcePosition: &ResourcePosition{Line: 14, Column: 9},
+			},
+		},
+		{
+			name:   "new object",
+			object: &NewEmptyKubeObject().SubObject,
+			field:  "spec",
+			expected: &Result{
+				Message: "msg", Severity: Error, ResourceRef: &ResourceRef{},
+				Field: &Field{Path: "spec"},
+				File:  &File{Index: -1},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SubObjectResult("msg", tc.object, tc.field, Error))
+		})
+	}
+}
+
+func TestResultsAt(t *testing.T) {
+	rl, err := ParseResourceList([]byte(locationInput))
+	assert.NoError(t, err)
+	spec := rl.Items[1].GetMap("spec")
+
+	var results Results
+	results.ErrorAt(spec, "replicas", "too few replicas")
+	results.WarningAt(spec, "template", "no pod labels")
+	results.InfoAt(spec, "", "validated")
+	assert.Equal(t, `[error] apps/v1/Deployment/default/nginx spec.replicas: too few replicas
+---
+[warning] apps/v1/Deployment/default/nginx spec.template: no pod labels
+---
+[info] apps/v1/Deployment/default/nginx spec: validated`, results.String())
+}
@@ -15,9 +15,11 @@
 package fn
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/kio"
@@ -27,55 +29,124 @@ import (
 // `input` can be
 // - a `ResourceListProcessor` which implements `Process` method
 // - a function `Runner` which implements `Run` method
-func AsMain(input interface{}) error {
+// - a function `Generator` which implements `Generate` method
+//
+// AsMain reads and writes the ResourceList the same way as Execute does. If the function panics, the stack trace is
+// printed to STDERR, and the returned error is a PanicError. See ExitCode.
+//
+// The ResourceList context (see ResourceList.Context) is cancelled when the function receives SIGINT or SIGTERM, or
+// when the timeout given by the TimeoutFlag or the TimeoutEnv is exceeded, so that the function can stop and report
+// it as an Error result rather than being killed without output. A function which does not stop within 5 seconds is
+// abandoned: the input ResourceList is written with the Error result (with the InputDirFlag, nothing is written),
+// and AsMain returns the error.
+//
+// With the SARIFFlag, AsMain also writes the results to a file in the SARIF format. See Results.ToSARIF.
+//
+// If the function is called with the DescribeFlag, AsMain prints the FunctionMetadata of `input` to STDOUT
+// instead. See Describer.
+//
+// For local debugging without kpt, the function can read the resources from a directory with the InputDirFlag,
+// write them to a directory with the OutputDirFlag (which can be the input directory), and read the functionConfig
+// from a file with the ConfigFlag. e.g.
+//
+//	go run . --input-dir=./pkg --output-dir=./pkg --config=./fn-config.yaml
+func AsMain(input interface{}, opts ...Option) error {
+	var tty *terminalReadWriter
 	err := func() error {
 		var p ResourceListProcessor
 		switch input := input.(type) {
-		case runnerProcessor:
-			p = input
-		case ResourceListProcessorFunc:
+		case ResourceListProcessor:
 			p = input
+		case Runner:
+			p = WithContext(context.Background(), input)
+		case Generator:
+			p = WithGenerator(context.Background(), input)
 		default:
 			return fmt.Errorf("unknown input type %T", input)
 		}
-		in, err := io.ReadAll(os.Stdin)
+		if describeRequested(os.Args[1:]) {
+			md, err := NewFunctionMetadata(input)
+			if err != nil {
+				return err
+			}
+			out, err := md.ToYAML()
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+		ctx, cancel, err := newMainContext(os.Args[1:])
 		if err != nil {
-			return fmt.Errorf("unable to read from stdin: %v", err)
+			return err
+		}
+		defer cancel()
+		var rw resourceListReadWriter = newByteReadWriter(os.Stdin, os.Stdout)
+		flags := parseLocalFlags(os.Args[1:])
+		if flags.enabled() {
+			rw = newLocalReadWriter(flags, os.Stdin, os.Stdout)
+		}
+		// The results written to a directory are already logged by the localReadWriter.
+		if flags.outputDir == "" && isTerminal(os.Stderr) {
+			tty = &terminalReadWriter{resourceListReadWriter: rw}
+			rw = tty
 		}
-		out, err := Run(p, in)
-		// If there is an error, we don't return the error immediately.
-		// We write out to stdout before returning any error.
-		_, outErr := os.Stdout.Write(out)
-		if outErr != nil {
-			return outErr
+		if sarifFile, _ := lookupArg(os.Args[1:], SARIFFlag); sarifFile != "" {
+			rw = &sarifReadWriter{resourceListReadWriter: rw, file: sarifFile, toolName: functionName(input)}
 		}
-		return err
+		o := newOptions(opts)
+		o.abandonCancelled = true
+		return execute(ctx, p, rw, o)
 	}()
-	if err != nil {
+	var results Results
+	switch {
+	case err == nil:
+	case tty != nil && errors.As(err, &results):
+		Logf("failed to evaluate function:\n%s", results.Render(tty.items, terminalColor()))
+	default:
 		Logf("failed to evaluate function: %v", err)
 	}
+	if err != nil {
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			Logf("\n%s", panicErr.Stack)
+		}
+	}
 	return err
 }
 
-// Run evaluates the function. input must be a resourceList in yaml format. An
-// updated resourceList will be returned.
-func Run(p ResourceListProcessor, input []byte) ([]byte, error) {
-	switch input := p.(type) {
-	case runnerProcessor:
-		p = input
-	case ResourceListProcessorFunc:
-		p = input
-	default:
-		return nil, fmt.Errorf("unknown input type %T", input)
+// Run evaluates the function. input must be a resourceList in yaml or json format. An
+// updated resourceList will be returned, in the same format as the input. The items are sorted by SortByGVKNN unless
+// WithItemOrder is given.
+func Run(p ResourceListProcessor, input []byte, opts ...Option) (_ []byte, err error) {
+	if p == nil {
+		return nil, fmt.Errorf("the ResourceListProcessor is nil")
 	}
-	rl, err := ParseResourceList(input)
+	root := startTrace("Run")
+	var rl *ResourceList
+	defer func() { endTrace(root, rl, err) }()
+	parse := root.child("parse")
+	rl, err = ParseResourceList(input)
+	parse.finish(err)
 	if err != nil {
 		return nil, err
 	}
-	success, fnErr := p.Process(rl)
-	out, yamlErr := rl.ToYAML()
-	if yamlErr != nil {
-		return out, yamlErr
+	rl.span = root
+	o := newOptions(opts)
+	rl.setLogResults(o.logResults)
+	inputResults := len(rl.Results)
+	success, fnErr := traceProcess(Use(p, o.middlewares...), rl)
+	success, fnErr = applyResultPolicy(o, rl, inputResults, success, fnErr)
+	if !sortOutput(rl, o.itemOrder) {
+		success = false
+	}
+	toBytes := rl.ToYAML
+	if isJSON(input) {
+		toBytes = rl.ToJSON
+	}
+	out, writeErr := toBytes()
+	if writeErr != nil {
+		return out, writeErr
 	}
 	if fnErr != nil {
 		return out, fnErr
@@ -86,9 +157,72 @@ func Run(p ResourceListProcessor, input []byte) ([]byte, error) {
 	return out, nil
 }
 
-func Execute(p ResourceListProcessor, r io.Reader, w io.Writer) error {
-	rw := &byteReadWriter{
-		kio.ByteReadWriter{
+// Execute reads the ResourceList from r, evaluates it with p and writes the updated ResourceList to w.
+// The internal annotations set by the orchestrator are neither added nor removed, so that the orchestrator
+// (e.g. kpt) can reconcile the output with its input. The items keep their order unless WithItemOrder is given.
+func Execute(p ResourceListProcessor, r io.Reader, w io.Writer, opts ...Option) error {
+	return execute(context.Background(), p, newByteReadWriter(r, w), newOptions(opts))
+}
+
+// ExecuteContext is Execute with the context of the evaluation, e.g. with a deadline. See ResourceList.Context.
+func ExecuteContext(ctx context.Context, p ResourceListProcessor, r io.Reader, w io.Writer, opts ...Option) error {
+	return execute(ctx, p, newByteReadWriter(r, w), newOptions(opts))
+}
+
+// processOrAbandon is traceProcess for AsMain. Since AsMain catches SIGINT and SIGTERM, a processor which does not
+// stop once ctx is done would keep the function running past the signal or the timeout. Such a processor is given
+// the cancelGracePeriod to stop, and is then abandoned with an Error result of the cause of ctx, while it is left
+// running until the process exits. Since it may still modify rl, the input ResourceList is parsed again from its raw
+// input and returned instead. If rl was not parsed from a raw input (e.g. with InputDirFlag), nil is returned, and the
+// output must not be written.
+func processOrAbandon(ctx context.Context, p ResourceListProcessor, rl *ResourceList) (*ResourceList, bool, error) {
+	type processed struct {
+		success bool
+		err     error
+	}
+	done := make(chan processed, 1)
+	go func() {
+		success, err := traceProcess(p, rl)
+		done <- processed{success, err}
+	}()
+	select {
+	case r := <-done:
+		return rl, r.success, r.err
+	case <-ctx.Done():
+	}
+	select {
+	case r := <-done:
+		return rl, r.success, r.err
+	case <-time.After(cancelGracePeriod):
+	}
+	err := fmt.Errorf("the function did not stop within %v: %w", cancelGracePeriod, context.Cause(ctx))
+	if rl.input == nil {
+		return nil, false, err
+	}
+	input, parseErr := ParseResourceList(rl.input)
+	if parseErr != nil {
+		return nil, false, err
+	}
+	input.ctx, input.logResults, input.logMu, input.span = rl.ctx, rl.logResults, rl.logMu, rl.span
+	input.Results = append(input.Results, ErrorResult(err))
+	return input, false, err
+}
+
+// sortOutput sorts the items in the order of the output. An unknown order does not drop the output: it is reported
+// as an Error result, and the items keep their order.
+func sortOutput(rl *ResourceList, order ItemOrder) bool {
+	rl.ItemOrder = order
+	if err := rl.SortItems(order); err != nil {
+		rl.Results = append(rl.Results, &Result{Message: err.Error(), Severity: Error})
+		rl.ItemOrder = PreserveOrder
+		return false
+	}
+	return true
+}
+
+func newByteReadWriter(r io.Reader, w io.Writer) *byteReadWriter {
+	return &byteReadWriter{
+		ByteReadWriter: kio.ByteReadWriter{
 			Reader: r,
 			Writer: w,
 			// We should not set the id annotation in the function, since we should not
@@ -99,18 +233,54 @@ func Execute(p ResourceListProcessor, r io.Reader, w io.Writer) error {
 			KeepReaderAnnotations: true,
 		},
 	}
-	return execute(p, rw)
 }
 
-func execute(p ResourceListProcessor, rw *byteReadWriter) error {
+// execute evaluates the ResourceList read from rw with p in ctx, and writes the output to rw.
+func execute(ctx context.Context, p ResourceListProcessor, rw resourceListReadWriter, o *options) (err error) {
+	if p == nil {
+		return fmt.Errorf("the ResourceListProcessor is nil")
+	}
+	root := startTrace("Execute")
+	var rl *ResourceList
+	defer func() { endTrace(root, rl, err) }()
 	// Read the input
-	rl, err := rw.Read()
+	read := root.child("read")
+	rl, err = rw.Read()
+	read.finish(err)
 	if err != nil {
 		return errors.WrapPrefixf(err, "failed to read ResourceList input")
 	}
-	success, fnErr := p.Process(rl)
+	rl.span = root
+	rl.SetContext(ctx)
+	rl.setLogResults(o.logResults)
+	inputResults := len(rl.Results)
+	var success bool
+	var fnErr error
+	if o.abandonCancelled {
+		var input *ResourceList
+		input, success, fnErr = processOrAbandon(ctx, Use(p, o.middlewares...), rl)
+		if input == nil {
+			// The abandoned processor may still modify rl, and the input cannot be restored.
+			rl = nil
+			return fnErr
+		}
+		rl = input
+	} else {
+		success, fnErr = traceProcess(Use(p, o.middlewares...), rl)
+	}
+	success, fnErr = applyResultPolicy(o, rl, inputResults, success, fnErr)
 	// Write the output
-	if err := rw.Write(rl); err != nil {
+	order := o.itemOrder
+	if order == "" {
+		order = PreserveOrder
+	}
+	if !sortOutput(rl, order) {
+		success = false
+	}
+	write := startSpan(rl, "write")
+	err = rw.Write(rl)
+	endSpan(rl, write, err)
+	if err != nil {
 		return errors.WrapPrefixf(err, "failed to write ResourceList output")
 	}
 	if fnErr != nil {
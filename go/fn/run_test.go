@@ -0,0 +1,126 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ResourceListProcessor = &removeAll{}
+
+type removeAll struct{}
+
+func (*removeAll) Process(rl *ResourceList) (bool, error) {
+	rl.Items = nil
+	return true, nil
+}
+
+// labelMiddleware wraps another processor, the same way users build their own middlewares.
+type labelMiddleware struct {
+	next ResourceListProcessor
+}
+
+func (m labelMiddleware) Process(rl *ResourceList) (bool, error) {
+	ok, err := m.next.Process(rl)
+	rl.Results.Infof("wrapped")
+	return ok, err
+}
+
+var runInput = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+    annotations:
+      internal.config.kubernetes.io/path: cm.yaml
+results:
+- message: from a previous function
+  severity: info
+`
+
+func TestRunAcceptsAnyProcessor(t *testing.T) {
+	out, err := Run(labelMiddleware{next: &removeAll{}}, []byte(runInput))
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+results:
+- message: from a previous function
+  severity: info
+- message: wrapped
+  severity: info
+`, string(out))
+}
+
+func TestAsMainAcceptsAnyProcessor(t *testing.T) {
+	testcases := map[string]interface{}{
+		"struct processor":   labelMiddleware{next: &removeAll{}},
+		"processor function": ResourceListProcessorFunc(labelMiddleware{next: &removeAll{}}.Process),
+	}
+	for description, input := range testcases {
+		var expected bytes.Buffer
+		err := Execute(input.(ResourceListProcessor), strings.NewReader(runInput), &expected)
+		assert.NoError(t, err, description)
+
+		actual := asMainWithStdio(t, input, runInput)
+		assert.Equal(t, expected.String(), actual, description)
+	}
+}
+
+func TestAsMainUnknownInput(t *testing.T) {
+	err := AsMain("not a function")
+	assert.EqualError(t, err, "unknown input type string")
+}
+
+// asMainWithStdio runs AsMain with `in` as STDIN and returns what has been written to STDOUT.
+func asMainWithStdio(t *testing.T, input interface{}, in string) string {
+	t.Helper()
+	dir := t.TempDir()
+	stdin, err := os.Create(dir + "/stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stdin.WriteString(in); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := os.Create(dir + "/stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdin, origStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	defer func() {
+		os.Stdin, os.Stdout = origStdin, origStdout
+		stdin.Close()
+		stdout.Close()
+	}()
+	if err = AsMain(input); err != nil {
+		t.Fatalf("AsMain failed: %v", err)
+	}
+	out, err := os.ReadFile(dir + "/stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -26,9 +26,16 @@ func WithContext(ctx context.Context, runner Runner) ResourceListProcessor {
 	return runnerProcessor{ctx: ctx, fnRunner: runner}
 }
 
+// WithGenerator is the Generator version of WithContext. The functionConfig is decoded to the Generator the same
+// way as to a Runner, and the Generator can add or delete `ResourceList.Items`.
+func WithGenerator(ctx context.Context, generator Generator) ResourceListProcessor {
+	return runnerProcessor{ctx: ctx, fnRunner: generator}
+}
+
 type runnerProcessor struct {
-	ctx      context.Context
-	fnRunner Runner
+	ctx context.Context
+	// fnRunner is either a Runner or a Generator.
+	fnRunner any
 }
 
 // EmptyFunctionConfig is a workaround solution to handle the case where kpt passes in a functionConfig placeholder
@@ -43,31 +50,60 @@ func EmptyFunctionConfig(o *KubeObject) bool {
 	return o.GetKind() == "ConfigMap" && o.GetName() == "function-input" && len(data) == 0
 }
 
-// Process assigns the ResourceList.FunctionConfig to Runner's attributes, and calls the Runner.Run methods (main method)
-// to run functions. The r.fnRunner accepts three kinds of functionConfig value:
+// Process assigns the ResourceList.FunctionConfig to Runner's attributes, and calls the Runner.Run (or Generator.Generate)
+// methods (main method) to run functions. The r.fnRunner accepts three kinds of functionConfig value:
 //  1. no function config, it only runs fnRunner.Run
-//  2. ConfigMap type, it requires the Runner instance to have one contributes of type map[string]string to receive the ConfigMap `.data` value.
+//  2. ConfigMap type, each `.data` key is decoded to the Runner field of the same json (or yaml) name, and converted to
+//     the field type. The keys that match no field are assigned to the first Runner field of type map[string]string.
 //  3. Runner type, it uses the Runner struct name as the FunctionConfig Kind. e.g. if the Runner is `SetNamespace`,
 //     the FunctionConfig should be `{"Kind": "SetNamespace", "apiVersion": "fn.kpt.dev/v1alpha1"}
+//     If the Runner implements VersionedConfig, the FunctionConfig should match one of its ConfigVersions instead.
+//
+// The `kptDefault` struct tags (see DefaultTag) are then applied to the unset Runner fields and the `kptValidate`
+// struct tags (see ValidateTag) are checked. The Runner is not run if the functionConfig is invalid.
 func (r runnerProcessor) Process(rl *ResourceList) (bool, error) {
+	if rl.copyRunner {
+		r = r.copy()
+	}
 	// Validate and Parse the input FunctionConfig to r.fnRunner
+	configPath := ""
 	if rl.FunctionConfig.IsEmpty() || EmptyFunctionConfig(rl.FunctionConfig) {
 		// functions may not need functionConfig.
 		rl.Results.Infof("`FunctionConfig` is not given")
 	} else {
 		err := r.config(rl.FunctionConfig)
 		if err != nil {
-			rl.Results.ErrorE(err)
+			rl.LogResult(err)
 			return false, nil
 		}
+		if rl.FunctionConfig.GroupKind() == (schema.GroupKind{Kind: "ConfigMap"}) {
+			configPath = "data"
+		}
+	}
+	// Apply the `kptDefault` and `kptValidate` struct tags of the Runner.
+	if err := defaultAndValidate(r.fnRunner, configPath, configFieldIsSet(rl.FunctionConfig)); err != nil {
+		rl.LogResult(err)
+		return false, nil
 	}
 	// Run the main function.
-	fnCtx := &Context{Context: r.ctx}
+	fnCtx := &Context{Context: newRunnerContext(r.ctx, rl), rl: rl}
 	results := new(Results)
-	shouldPass := r.fnRunner.Run(fnCtx, rl.FunctionConfig, rl.Items, results)
+	var shouldPass bool
+	switch runner := r.fnRunner.(type) {
+	case Generator:
+		shouldPass = runner.Generate(fnCtx, rl.FunctionConfig, &rl.Items, results)
+	case Runner:
+		shouldPass = runner.Run(fnCtx, rl.FunctionConfig, rl.Items, results)
+	default:
+		return false, fmt.Errorf("the `fnRunner` should be a Runner or a Generator, got %T", r.fnRunner)
+	}
 	// If running in a pipeline, the ResourceList may already have results from previous function runs.
 	// Thus, we only append new results to the end.
 	rl.Results = append(rl.Results, *results...)
+	// The Runner may have stopped early because its context is done.
+	if err := contextError(rl); err != nil {
+		return false, err
+	}
 	return shouldPass, nil
 }
 
@@ -76,13 +112,17 @@ func (r *runnerProcessor) config(o *KubeObject) error {
 		return fmt.Errorf("the object which implements `ResourceListProcessor` interface requires a `Runner` or `fnRunner` attribute," +
 			" got nil")
 	}
-	switch o.GroupKind() {
-	case schema.GroupKind{Kind: "ConfigMap"}:
+	if o.GroupKind() == (schema.GroupKind{Kind: "ConfigMap"}) {
 		data, _, err := o.NestedStringMap("data")
 		if data == nil {
 			return err
 		}
 		return assignCMDataToFn(r.fnRunner, data)
+	}
+	if versioned, ok := r.fnRunner.(VersionedConfig); ok {
+		return decodeConfigVersion(r.fnRunner, versioned.ConfigVersions(), o)
+	}
+	switch o.GroupKind() {
 	case schema.GroupKind{Group: KptFunctionGroup, Kind: asFnName(r.fnRunner)}:
 		return o.As(r.fnRunner)
 	default:
@@ -90,29 +130,92 @@ func (r *runnerProcessor) config(o *KubeObject) error {
 	}
 }
 
-func asFnName(runner Runner) string {
-	// Validate the fnRunner type to avoid panic.
-	kind := reflect.ValueOf(runner).Kind()
-	if kind != reflect.Interface && kind != reflect.Ptr {
-		return ""
+// Concurrent returns a ResourceListProcessor that can evaluate ResourceLists concurrently, e.g. in a server. A Runner
+// (or Generator) given by WithContext (or WithGenerator), possibly wrapped with middlewares (see Middleware), is
+// copied for each evaluation, with its exported maps, slices and pointers, so that the evaluations do not share the
+// functionConfig decoded to it. The other
+// processors are returned as they are, and should be safe for concurrent use.
+func Concurrent(p ResourceListProcessor) ResourceListProcessor {
+	if _, ok := unwrapRunner(p); !ok {
+		return p
 	}
-	return reflect.ValueOf(runner).Elem().Type().Name()
+	return ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.copyRunner = true
+		return p.Process(rl)
+	})
 }
 
-func assignCMDataToFn(runner Runner, data map[string]string) error {
-	obj := reflect.ValueOf(runner).Elem()
-	if obj.Kind() != reflect.Struct {
-		return fmt.Errorf("the ConfigMap is not of a struct, got %v", obj.Kind().String())
+// copy returns the runnerProcessor of a copy of the Runner (or Generator), so that the concurrent evaluations do not
+// share the functionConfig decoded to the Runner. The functionConfig is decoded to the exported fields only, so the
+// maps, slices and pointers of those are copied too, e.g. a map initialized by the caller, while the unexported
+// fields, e.g. a client, are shared.
+func (r runnerProcessor) copy() runnerProcessor {
+	v := reflect.ValueOf(r.fnRunner)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return r
 	}
-	stringMap := reflect.MapOf(reflect.TypeOf("string"), reflect.TypeOf("string"))
-	for i := 0; i < obj.NumField(); i++ {
-		if obj.Field(i).Kind() == reflect.Map && obj.Field(i).Type() == stringMap {
-			if obj.Field(i).CanSet() {
-				obj.Field(i).Set(reflect.ValueOf(data))
+	return runnerProcessor{ctx: r.ctx, fnRunner: deepCopy(v, map[uintptr]reflect.Value{}).Interface()}
+}
+
+// deepCopy returns a copy of v with its own maps, slices and pointers, except those of the unexported struct fields.
+// `copied` are the copies of the pointers already copied, so that a cycle is copied once.
+func deepCopy(v reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, found := copied[v.Pointer()]; found {
+			return c
+		}
+		c := reflect.New(v.Elem().Type())
+		copied[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < c.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), copied))
 			}
-			return nil
 		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value(), copied))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copied))
+		return c
+	default:
+		return v
 	}
-	return fmt.Errorf("unable to assign the given ConfigMap `.data` to FunctionConfig %v. please make sure the %v "+
-		"has a field of type map[string]string", asFnName(runner), asFnName(runner))
+}
+
+func asFnName(runner any) string {
+	// Validate the fnRunner type to avoid panic.
+	kind := reflect.ValueOf(runner).Kind()
+	if kind != reflect.Interface && kind != reflect.Ptr {
+		return ""
+	}
+	return reflect.ValueOf(runner).Elem().Type().Name()
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -168,8 +169,8 @@ data:
     k2: v2
 `),
 			runner: &SetTestNoMapString{},
-			expectedErr: "unable to assign the given ConfigMap `.data` to FunctionConfig SetTestNoMapString. " +
-				"please make sure the SetTestNoMapString has a field of type map[string]string",
+			expectedErr: "[error] data.k1: FunctionConfig SetTestNoMapString has no field matching ConfigMap key \"k1\"\n\n" +
+				"[error] data.k2: FunctionConfig SetTestNoMapString has no field matching ConfigMap key \"k2\"",
 			expectedArgsToString: "",
 		},
 		"functionConfig is custom kind, validate the GVK ": {
@@ -207,3 +208,164 @@ args: test
 		}
 	}
 }
+
+var _ Generator = &GenerateTest{}
+
+type GenerateTest struct {
+	Names []string `json:"names,omitempty"`
+	Drop  string   `json:"drop,omitempty"`
+}
+
+func (g *GenerateTest) Generate(_ *Context, _ *KubeObject, items *KubeObjects, results *Results) bool {
+	*items = items.WhereNot(IsName(g.Drop))
+	for _, name := range g.Names {
+		obj := NewEmptyKubeObject()
+		_ = obj.SetAPIVersion("v1")
+		_ = obj.SetKind("ConfigMap")
+		_ = obj.SetName(name)
+		items.Upsert(obj)
+	}
+	results.Infof("generated %d ConfigMaps", len(g.Names))
+	return true
+}
+
+func TestGeneratorProcess(t *testing.T) {
+	rl, err := ParseResourceList([]byte(`
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: old
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: GenerateTest
+  metadata:
+    name: test
+  names:
+  - a
+  - b
+  drop: old
+`))
+	assert.NoError(t, err)
+	ok, err := WithGenerator(context.TODO(), &GenerateTest{}).Process(rl)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	var names []string
+	for _, item := range rl.Items {
+		names = append(names, item.GetName())
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Equal(t, "[info]: generated 2 ConfigMaps", rl.Results.String())
+}
+
+type TypedConfig struct {
+	Name     string            `json:"name,omitempty"`
+	Replicas int               `json:"replicas,omitempty"`
+	Enabled  bool              `json:"enabled,omitempty"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Timeout  time.Duration     `json:"timeout,omitempty"`
+	Ports    []int32           `json:"ports,omitempty"`
+	Tags     []string          `yaml:"tags,omitempty"`
+	Limit    *int              `json:"limit,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Target   *TypedTarget      `json:"target,omitempty"`
+	Other    string
+}
+
+type TypedTarget struct {
+	Kind       string   `json:"kind,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+func (*TypedConfig) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	return true
+}
+
+// NamespaceConfig has both a typed field and a map of the whole ConfigMap `.data`.
+type NamespaceConfig struct {
+	Data      map[string]string
+	Namespace string `json:"namespace"`
+}
+
+func (*NamespaceConfig) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	return true
+}
+
+func TestAssignCMDataToCatchAll(t *testing.T) {
+	config := &NamespaceConfig{}
+	err := assignCMDataToFn(config, map[string]string{"namespace": "foo", "other": "x"})
+	assert.NoError(t, err)
+	// The map still receives the keys of the typed fields, as it did before they were decoded.
+	assert.Equal(t, &NamespaceConfig{Data: map[string]string{"namespace": "foo", "other": "x"}, Namespace: "foo"}, config)
+}
+
+func TestAssignCMDataToFn(t *testing.T) {
+	limit := 3
+	testdata := map[string]struct {
+		data        map[string]string
+		expected    TypedConfig
+		expectedErr string
+	}{
+		"scalar types": {
+			data: map[string]string{
+				"name":     "test",
+				"replicas": "2",
+				"enabled":  "true",
+				"ratio":    "0.5",
+				"timeout":  "1m30s",
+				"limit":    "3",
+				"other":    "case-insensitive",
+			},
+			expected: TypedConfig{Name: "test", Replicas: 2, Enabled: true, Ratio: 0.5, Timeout: 90 * time.Second,
+				Limit: &limit, Other: "case-insensitive", Labels: map[string]string{
+					"name": "test", "replicas": "2", "enabled": "true", "ratio": "0.5", "timeout": "1m30s",
+					"limit": "3", "other": "case-insensitive",
+				}},
+		},
+		"lists": {
+			data: map[string]string{
+				"ports": "80, 443",
+				"tags":  "[a, b]",
+			},
+			expected: TypedConfig{Ports: []int32{80, 443}, Tags: []string{"a", "b"},
+				Labels: map[string]string{"ports": "80, 443", "tags": "[a, b]"}},
+		},
+		"nested YAML objects": {
+			data: map[string]string{
+				"labels": "app: foo",
+				"target": "kind: Deployment\nnamespaces:\n- dev\n- prod\n",
+			},
+			expected: TypedConfig{Labels: map[string]string{"app": "foo"},
+				Target: &TypedTarget{Kind: "Deployment", Namespaces: []string{"dev", "prod"}}},
+		},
+		"all keys go to the map[string]string field": {
+			data: map[string]string{
+				"name": "test",
+				"app":  "foo",
+			},
+			expected: TypedConfig{Name: "test", Labels: map[string]string{"name": "test", "app": "foo"}},
+		},
+		"invalid values": {
+			data: map[string]string{
+				"replicas": "two",
+				"enabled":  "yes",
+			},
+			expectedErr: "[error] data.enabled: unable to assign ConfigMap key \"enabled\" to FunctionConfig TypedConfig: " +
+				"strconv.ParseBool: parsing \"yes\": invalid syntax\n\n" +
+				"[error] data.replicas: unable to assign ConfigMap key \"replicas\" to FunctionConfig TypedConfig: " +
+				"strconv.ParseInt: parsing \"two\": invalid syntax",
+		},
+	}
+	for description, test := range testdata {
+		actual := &TypedConfig{}
+		err := assignCMDataToFn(actual, test.data)
+		if test.expectedErr != "" {
+			assert.EqualError(t, err, test.expectedErr, description)
+			continue
+		}
+		assert.NoError(t, err, description)
+		assert.Equal(t, test.expected, *actual, description)
+	}
+}
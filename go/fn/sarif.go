@@ -0,0 +1,200 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// SARIFFlag makes AsMain also write the function results to a file in the SARIF format, e.g.
+	// `--sarif=results.sarif`, so that they can be uploaded as code scanning findings.
+	SARIFFlag = "--sarif"
+
+	// RuleTag is the Result tag of the rule which made the result, e.g. `min-replicas`. It is the ruleId of the
+	// result in the SARIF format, which defaults to the function name.
+	RuleTag = "fn.kpt.dev/rule"
+
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Fixes      []sarifFix        `json:"fixes,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+// sarifArtifactChange has no replacements, since the region of the field in the file is not known. The field path
+// and the proposed value are in its properties instead.
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Properties       map[string]string     `json:"properties"`
+}
+
+// ToSARIF converts the results to a SARIF 2.1.0 log of a single run of the tool `toolName`, e.g. the function name.
+// The RuleTag (or else toolName) is mapped to the ruleId, the Severity to the level (Info is a `note`), the File path
+// to the physical location, the ResourceRef and the Field.Path to the logical locations, the Field.ProposedValue to a
+// fix of the File, and the Tags to the properties. The ResourcePosition is relative to the resource, not to the file,
+// so it is not a region of the physical location, and the fix describes the change of the field (its `fieldPath` and
+// `proposedValue` properties) rather than replacing a region of the file.
+func (r Results) ToSARIF(toolName string) ([]byte, error) {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName}},
+		Results: []sarifResult{},
+	}
+	for _, result := range r {
+		if result == nil {
+			continue
+		}
+		run.Results = append(run.Results, result.toSARIF(toolName))
+	}
+	log := sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func (i Result) toSARIF(toolName string) sarifResult {
+	out := sarifResult{
+		RuleID:     toolName,
+		Level:      sarifLevel(i.Severity),
+		Message:    sarifMessage{Text: i.Message},
+		Properties: i.Tags,
+	}
+	if rule := i.Tags[RuleTag]; rule != "" {
+		out.RuleID = rule
+	}
+	var location sarifLocation
+	if i.File != nil && i.File.Path != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(i.File.Path)}}
+	}
+	if i.ResourceRef != nil {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{
+			Name:               i.ResourceRef.Name,
+			FullyQualifiedName: resourceRefString(i.ResourceRef),
+			Kind:               "resource",
+		})
+	}
+	if i.Field != nil && i.Field.Path != "" {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{
+			Name:               i.Field.Path,
+			FullyQualifiedName: i.Field.Path,
+			Kind:               "property",
+		})
+	}
+	if location.PhysicalLocation != nil || len(location.LogicalLocations) > 0 {
+		out.Locations = []sarifLocation{location}
+	}
+	if i.Field != nil && i.Field.Path != "" && i.Field.ProposedValue != nil && location.PhysicalLocation != nil {
+		value := formatValue(i.Field.ProposedValue)
+		out.Fixes = []sarifFix{{
+			Description: sarifMessage{Text: fmt.Sprintf("Set %s to %s", i.Field.Path, value)},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: location.PhysicalLocation.ArtifactLocation,
+				Properties:       map[string]string{"fieldPath": i.Field.Path, "proposedValue": value},
+			}},
+		}}
+	}
+	return out
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifURI returns the relative URI reference of the file path.
+func sarifURI(path string) string {
+	return strings.ReplaceAll(path, `\`, "/")
+}
+
+// sarifReadWriter also writes the results of the ResourceList to a SARIF file.
+type sarifReadWriter struct {
+	resourceListReadWriter
+	file     string
+	toolName string
+}
+
+func (rw *sarifReadWriter) Write(rl *ResourceList) error {
+	if err := rw.resourceListReadWriter.Write(rl); err != nil {
+		return err
+	}
+	out, err := rl.Results.ToSARIF(rw.toolName)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(rw.file, out, 0644); err != nil {
+		return fmt.Errorf("failed to write the SARIF results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultsToSARIF(t *testing.T) {
+	testcases := []struct {
+		name     string
+		results  Results
+		expected string
+	}{
+		{
+			name: "no results",
+			expected: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "set-labels"
+        }
+      },
+      "results": []
+    }
+  ]
+}`,
+		},
+		{
+			name: "locations",
+			results: Results{
+				{
+					Message:  "replicas must be at least 3",
+					Severity: Error,
+					ResourceRef: &ResourceRef{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "nginx",
+						Namespace:  "default",
+					},
+					Field: &Field{Path: "spec.replicas", CurrentValue: 1, ProposedValue: 3},
+					File:  &File{Path: "deploy/nginx.yaml"},
+					Tags:  map[string]string{RuleTag: "min-replicas"},
+				},
+				{Message: "no label selector", Severity: Warning, ResourceRef: &ResourceRef{Kind: "Service", Name: "nginx"}},
+				{Message: "2 resources processed"},
+			},
+			expected: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "set-labels"
+        }
+      },
+      "results": [
+        {
+          "ruleId": "min-replicas",
+          "level": "error",
+          "message": {
+            "text": "replicas must be at least 3"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "deploy/nginx.yaml"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "name": "nginx",
+                  "fullyQualifiedName": "apps/v1/Deployment/default/nginx",
+                  "kind": "resource"
+                },
+                {
+                  "name": "spec.replicas",
+                  "fullyQualifiedName": "spec.replicas",
+                  "kind": "property"
+                }
+              ]
+            }
+          ],
+          "fixes": [
+            {
+              "description": {
+                "text": "Set spec.replicas to 3"
+              },
+              "artifactChanges": [
+                {
+                  "artifactLocation": {
+                    "uri": "deploy/nginx.yaml"
+                  },
+                  "properties": {
+                    "fieldPath": "spec.replicas",
+                    "proposedValue": "3"
+                  }
+                }
+              ]
+            }
+          ],
+          "properties": {
+            "fn.kpt.dev/rule": "min-replicas"
+          }
+        },
+        {
+          "ruleId": "set-labels",
+          "level": "warning",
+          "message": {
+            "text": "no label selector"
+          },
+          "locations": [
+            {
+              "logicalLocations": [
+                {
+                  "name": "nginx",
+                  "fullyQualifiedName": "Service/nginx",
+                  "kind": "resource"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "set-labels",
+          "level": "note",
+          "message": {
+            "text": "2 resources processed"
+          }
+        }
+      ]
+    }
+  ]
+}`,
+		},
+		{
+			name: "resource position",
+			results: Results{
+				{
+					Message:          "enabled must be a string",
+					Severity:         Error,
+					Field:            &Field{Path: "data.enabled", CurrentValue: true, ProposedValue: "yes"},
+					File:             &File{Path: "config.yaml"},
+					ResourcePosition: &ResourcePosition{Line: 10, Column: 3},
+				},
+			},
+			expected: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "set-labels"
+        }
+      },
+      "results": [
+        {
+          "ruleId": "set-labels",
+          "level": "error",
+          "message": {
+            "text": "enabled must be a string"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "config.yaml"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "name": "data.enabled",
+                  "fullyQualifiedName": "data.enabled",
+                  "kind": "property"
+                }
+              ]
+            }
+          ],
+          "fixes": [
+            {
+              "description": {
+                "text": "Set data.enabled to \"yes\""
+              },
+              "artifactChanges": [
+                {
+                  "artifactLocation": {
+                    "uri": "config.yaml"
+                  },
+                  "properties": {
+                    "fieldPath": "data.enabled",
+                    "proposedValue": "\"yes\""
+                  }
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.results.ToSARIF("set-labels")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestSARIFReadWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "results.sarif")
+	var out bytes.Buffer
+	rw := &sarifReadWriter{
+		resourceListReadWriter: newByteReadWriter(strings.NewReader(runInput), &out),
+		file:                   file,
+		toolName:               "remove-all",
+	}
+	err := execute(t.Context(), &removeAll{}, rw, newOptions(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "kind: ResourceList")
+
+	sarif, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Contains(t, string(sarif), `"name": "remove-all"`)
+	assert.Contains(t, string(sarif), `"text": "from a previous function"`)
+}
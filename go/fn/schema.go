@@ -0,0 +1,431 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// JSONSchemaProps is the subset of the OpenAPI v3 schema that is used to describe a functionConfig in a
+// CustomResourceDefinition. We intentionally do not depend on k8s.io/apiextensions-apiserver, see go.mod.
+type JSONSchemaProps struct {
+	Type                   string                     `yaml:"type,omitempty" json:"type,omitempty"`
+	Format                 string                     `yaml:"format,omitempty" json:"format,omitempty"`
+	Description            string                     `yaml:"description,omitempty" json:"description,omitempty"`
+	Default                any                        `yaml:"default,omitempty" json:"default,omitempty"`
+	Enum                   []any                      `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Minimum                *float64                   `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Maximum                *float64                   `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	MinLength              *int64                     `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength              *int64                     `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	MinItems               *int64                     `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems               *int64                     `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	MinProperties          *int64                     `yaml:"minProperties,omitempty" json:"minProperties,omitempty"`
+	MaxProperties          *int64                     `yaml:"maxProperties,omitempty" json:"maxProperties,omitempty"`
+	Pattern                string                     `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Items                  *JSONSchemaProps           `yaml:"items,omitempty" json:"items,omitempty"`
+	Properties             map[string]JSONSchemaProps `yaml:"properties,omitempty" json:"properties,omitempty"`
+	AdditionalProperties   *JSONSchemaProps           `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	Required               []string                   `yaml:"required,omitempty" json:"required,omitempty"`
+	XPreserveUnknownFields *bool                      `yaml:"x-kubernetes-preserve-unknown-fields,omitempty" json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XValidations           []ValidationRule           `yaml:"x-kubernetes-validations,omitempty" json:"x-kubernetes-validations,omitempty"`
+}
+
+// ValidationRule is a CEL validation rule of a CustomResourceDefinition schema.
+type ValidationRule struct {
+	Rule    string `yaml:"rule" json:"rule"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// FieldDocs are the doc comments of Go types and struct fields, keyed by "TypeName" and "TypeName.FieldName".
+type FieldDocs map[string]string
+
+// ParseFieldDocs reads the doc comments of the types and struct fields declared in the Go package of dir.
+// Test files are skipped.
+func ParseFieldDocs(dir string) (FieldDocs, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	docs := FieldDocs{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", file, err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				if doc != nil {
+					docs[typeSpec.Name.Name] = strings.TrimSpace(doc.Text())
+				}
+				st, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if field.Doc == nil {
+						continue
+					}
+					for _, name := range field.Names {
+						docs[typeSpec.Name.Name+"."+name.Name] = strings.TrimSpace(field.Doc.Text())
+					}
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// ConfigSchema reflects over the Runner (or Generator) struct and returns the OpenAPI v3 schema of its
+// functionConfig. The field names come from the json tags, the descriptions from docs (which can be nil), and the
+// defaults and validations from the DefaultTag and ValidateTag struct tags.
+func ConfigSchema(runner any, docs FieldDocs) (*JSONSchemaProps, error) {
+	t := reflect.TypeOf(runner)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the Runner should be a struct or a pointer to a struct, got %T", runner)
+	}
+	g := &schemaGenerator{docs: docs, visiting: map[reflect.Type]bool{}}
+	s, err := g.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	if s.Properties == nil {
+		s.Properties = map[string]JSONSchemaProps{}
+	}
+	s.Properties["apiVersion"] = JSONSchemaProps{Type: "string"}
+	s.Properties["kind"] = JSONSchemaProps{Type: "string"}
+	s.Properties["metadata"] = JSONSchemaProps{Type: "object"}
+	return s, nil
+}
+
+// ConfigCRD returns the CustomResourceDefinition of the Runner functionConfig, whose kind is the Runner struct name
+// and whose group and version are KptFunctionGroup and KptFunctionVersion. See ConfigSchema.
+func ConfigCRD(runner any, docs FieldDocs) (*KubeObject, error) {
+	s, err := ConfigSchema(runner, docs)
+	if err != nil {
+		return nil, err
+	}
+	kind := asFnName(runner)
+	if kind == "" {
+		return nil, fmt.Errorf("unable to get the functionConfig kind of %T", runner)
+	}
+	singular := strings.ToLower(kind)
+	plural := pluralize(singular)
+	crd := map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name": plural + "." + KptFunctionGroup,
+		},
+		"spec": map[string]any{
+			"group": KptFunctionGroup,
+			"names": map[string]any{
+				"kind":     kind,
+				"listKind": kind + "List",
+				"plural":   plural,
+				"singular": singular,
+			},
+			"scope": "Namespaced",
+			"versions": []any{
+				map[string]any{
+					"name":    KptFunctionVersion,
+					"served":  true,
+					"storage": true,
+					"schema": map[string]any{
+						"openAPIV3Schema": s,
+					},
+				},
+			},
+		},
+	}
+	// Encode with the yaml tags rather than KubeObject.SetFromTypedObject, which relies on the json tags and would
+	// turn the integers (e.g. default values) into floats.
+	b, err := yaml.Marshal(crd)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKubeObject(b)
+}
+
+type schemaGenerator struct {
+	docs FieldDocs
+	// visiting guards against recursive types.
+	visiting map[reflect.Type]bool
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) (*JSONSchemaProps, error) {
+	if t == durationType {
+		// KubeObject.As decodes a time.Duration from its json form, which is in nanoseconds.
+		return &JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return &JSONSchemaProps{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchemaProps{Type: "boolean"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &JSONSchemaProps{Type: "integer", Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &JSONSchemaProps{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &JSONSchemaProps{Type: "number", Format: "double"}, nil
+	case reflect.Interface:
+		preserve := true
+		return &JSONSchemaProps{XPreserveUnknownFields: &preserve}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchemaProps{Type: "string", Format: "byte"}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchemaProps{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %v", t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchemaProps{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if g.visiting[t] {
+			preserve := true
+			return &JSONSchemaProps{Type: "object", XPreserveUnknownFields: &preserve}, nil
+		}
+		g.visiting[t] = true
+		defer delete(g.visiting, t)
+		s := &JSONSchemaProps{Type: "object", Description: g.docs[t.Name()]}
+		if err := g.addProperties(s, t); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t)
+	}
+}
+
+// addProperties adds the struct fields of t to s, including the ones promoted from embedded structs.
+func (g *schemaGenerator) addProperties(s *JSONSchemaProps, t reflect.Type) error {
+	exclusiveGroups := map[string][]string{}
+	var groups []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		key, hasTag := fieldKey(sf)
+		if key == "-" {
+			continue
+		}
+		if sf.Anonymous && !hasTag && sf.Type.Kind() == reflect.Struct {
+			if err := g.addProperties(s, sf.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		prop, err := g.schema(sf.Type)
+		if err != nil {
+			return fmt.Errorf("field %v.%v: %w", t.Name(), sf.Name, err)
+		}
+		if doc, found := g.docs[t.Name()+"."+sf.Name]; found {
+			prop.Description = doc
+		}
+		rules, err := parseFieldRules(sf.Tag.Get(ValidateTag))
+		if err != nil {
+			return fmt.Errorf("field %v.%v: %w", t.Name(), sf.Name, err)
+		}
+		if err = applyFieldRules(prop, sf, rules); err != nil {
+			return fmt.Errorf("field %v.%v: %w", t.Name(), sf.Name, err)
+		}
+		if rules.required {
+			s.Required = append(s.Required, key)
+		}
+		if rules.exclusive != "" {
+			if _, found := exclusiveGroups[rules.exclusive]; !found {
+				groups = append(groups, rules.exclusive)
+			}
+			exclusiveGroups[rules.exclusive] = append(exclusiveGroups[rules.exclusive], key)
+		}
+		if s.Properties == nil {
+			s.Properties = map[string]JSONSchemaProps{}
+		}
+		s.Properties[key] = *prop
+	}
+	for _, group := range groups {
+		keys := exclusiveGroups[group]
+		if len(keys) < 2 {
+			continue
+		}
+		var has []string
+		for _, key := range keys {
+			has = append(has, fmt.Sprintf("has(self.%v)", key))
+		}
+		s.XValidations = append(s.XValidations, ValidationRule{
+			Rule:    fmt.Sprintf("[%v].filter(x, x).size() <= 1", strings.Join(has, ", ")),
+			Message: fmt.Sprintf("fields %v are mutually exclusive", strings.Join(keys, ", ")),
+		})
+	}
+	return nil
+}
+
+// applyFieldRules translates the DefaultTag and ValidateTag of a struct field to the schema.
+func applyFieldRules(prop *JSONSchemaProps, sf reflect.StructField, rules fieldRules) error {
+	if def, found := sf.Tag.Lookup(DefaultTag); found {
+		v, err := typedValue(sf.Type, def)
+		if err != nil {
+			return fmt.Errorf("invalid default value %q: %w", def, err)
+		}
+		prop.Default = v
+	}
+	for _, e := range rules.enum {
+		elemType := sf.Type
+		if elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+		v, err := typedValue(elemType, e)
+		if err != nil {
+			return fmt.Errorf("invalid enum value %q: %w", e, err)
+		}
+		target := prop
+		if prop.Type == "array" && prop.Items != nil {
+			target = prop.Items
+		}
+		target.Enum = append(target.Enum, v)
+	}
+	if rules.pattern != nil {
+		target := prop
+		if prop.Type == "array" && prop.Items != nil {
+			target = prop.Items
+		}
+		target.Pattern = rules.pattern.String()
+	}
+	for _, bound := range []struct {
+		rule  string
+		isMin bool
+	}{{rules.min, true}, {rules.max, false}} {
+		if bound.rule == "" {
+			continue
+		}
+		if err := applyBound(prop, sf.Type, bound.rule, bound.isMin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBound(prop *JSONSchemaProps, t reflect.Type, rule string, isMin bool) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch prop.Type {
+	case "integer", "number":
+		v, err := strconv.ParseFloat(rule, 64)
+		if t == durationType {
+			var d time.Duration
+			d, err = time.ParseDuration(rule)
+			v = float64(d)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid bound %q: %w", rule, err)
+		}
+		if isMin {
+			prop.Minimum = &v
+		} else {
+			prop.Maximum = &v
+		}
+	case "string", "array", "object":
+		n, err := strconv.ParseInt(rule, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid bound %q: %w", rule, err)
+		}
+		bounds := map[string][2]**int64{
+			"string": {&prop.MinLength, &prop.MaxLength},
+			"array":  {&prop.MinItems, &prop.MaxItems},
+			"object": {&prop.MinProperties, &prop.MaxProperties},
+		}[prop.Type]
+		if isMin {
+			*bounds[0] = &n
+		} else {
+			*bounds[1] = &n
+		}
+	}
+	return nil
+}
+
+// typedValue converts the struct tag value s to the type t, the same way as a ConfigMap `.data` value.
+func typedValue(t reflect.Type, s string) (any, error) {
+	v := reflect.New(t).Elem()
+	if err := setFromString(v, s); err != nil {
+		return nil, err
+	}
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Type() == durationType {
+		// Same as the schema type, see schemaGenerator.schema.
+		return v.Int(), nil
+	}
+	return v.Interface(), nil
+}
+
+func pluralize(singular string) string {
+	switch {
+	case strings.HasSuffix(singular, "s"), strings.HasSuffix(singular, "x"),
+		strings.HasSuffix(singular, "ch"), strings.HasSuffix(singular, "sh"):
+		return singular + "es"
+	case len(singular) > 1 && strings.HasSuffix(singular, "y") && !strings.ContainsAny(singular[len(singular)-2:len(singular)-1], "aeiou"):
+		return singular[:len(singular)-1] + "ies"
+	default:
+		return singular + "s"
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldDocs(t *testing.T) {
+	dir := t.TempDir()
+	src := `package main
+
+// SetImage sets the container images.
+type SetImage struct {
+	// Image is the new image name.
+	Image string ` + "`json:\"image\"`" + `
+	Tag string
+}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0600))
+	docs, err := ParseFieldDocs(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldDocs{
+		"SetImage":       "SetImage sets the container images.",
+		"SetImage.Image": "Image is the new image name.",
+	}, docs)
+}
+
+func TestConfigCRD(t *testing.T) {
+	docs := FieldDocs{
+		"ValidatedConfig":      "ValidatedConfig is used to test the functionConfig validation.",
+		"ValidatedConfig.Name": "Name is the application name.",
+	}
+	crd, err := ConfigCRD(&ValidatedConfig{}, docs)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: validatedconfigs.fn.kpt.dev
+spec:
+  group: fn.kpt.dev
+  names:
+    kind: ValidatedConfig
+    listKind: ValidatedConfigList
+    plural: validatedconfigs
+    singular: validatedconfig
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        type: object
+        description: ValidatedConfig is used to test the functionConfig validation.
+        properties:
+          apiVersion:
+            type: string
+          build:
+            type: object
+            properties:
+              context:
+                type: string
+            required:
+            - context
+          image:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          mode:
+            type: string
+            default: merge
+            enum:
+            - merge
+            - replace
+          name:
+            type: string
+            description: Name is the application name.
+            pattern: ^[a-z]+(-[a-z]+)*$
+          replicas:
+            type: integer
+            format: int64
+            default: 1
+            minimum: 1
+            maximum: 5
+          tags:
+            type: array
+            maxItems: 2
+            items:
+              type: string
+          timeout:
+            type: integer
+            format: int64
+            default: 30000000000
+            maximum: 6e+10
+        required:
+        - name
+        x-kubernetes-validations:
+        - rule: '[has(self.image), has(self.build)].filter(x, x).size() <= 1'
+          message: fields image, build are mutually exclusive
+    served: true
+    storage: true
+`, crd.String())
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+// Scope makes the processor see only the items the selector matches, e.g. IsGroupKind or MatchesSelector, so that a
+// generic processor can be applied to some kinds only:
+//
+//	fn.Use(setReplicas, fn.Scope(fn.IsGroupKind(schema.GroupKind{Group: "apps", Kind: "Deployment"})))
+//
+// The changes of the processor are merged back into the ResourceList: the modified items are updated, the deleted
+// items are removed, and the added items are inserted after the item they follow in the view. The items out of the
+// view keep their place, and the items left in the view fill their original slots, in the order of the view. The
+// results and the functionConfig are shared with the view.
+func Scope(selector func(obj *KubeObject) bool) Middleware {
+	return func(next ResourceListProcessor) ResourceListProcessor {
+		return wrap(next, func(rl *ResourceList) (bool, error) {
+			view := &ResourceList{
+				Items:          rl.Items.Where(selector),
+				FunctionConfig: rl.FunctionConfig,
+				Results:        rl.Results,
+				ItemOrder:      rl.ItemOrder,
+				ctx:            rl.ctx,
+				logResults:     rl.logResults,
+				logMu:          rl.logMu,
+				span:           rl.span,
+				copyRunner:     rl.copyRunner,
+			}
+			selected := make(map[*KubeObject]bool, len(view.Items))
+			for _, obj := range view.Items {
+				selected[obj] = true
+			}
+			// Merge back even if the processor panics, so that the results are not lost.
+			defer func() {
+				rl.Items = mergeView(rl.Items, selected, view.Items)
+				rl.FunctionConfig = view.FunctionConfig
+				rl.Results = view.Results
+			}()
+			return next.Process(view)
+		})
+	}
+}
+
+// mergeView returns the items with the `selected` items replaced by the items of the view.
+func mergeView(items KubeObjects, selected map[*KubeObject]bool, view KubeObjects) KubeObjects {
+	// Group the items of the view by the selected item they follow. The added items before the first selected item
+	// of the view are in the first group.
+	var groups []KubeObjects
+	var added KubeObjects
+	seen := map[*KubeObject]bool{}
+	for _, obj := range view {
+		if obj == nil || seen[obj] {
+			continue
+		}
+		seen[obj] = true
+		if !selected[obj] {
+			if len(groups) == 0 {
+				added = append(added, obj)
+			} else {
+				groups[len(groups)-1] = append(groups[len(groups)-1], obj)
+			}
+			continue
+		}
+		groups = append(groups, append(added, obj))
+		added = nil
+	}
+
+	out := make(KubeObjects, 0, len(items)+len(view))
+	first, slot := true, 0
+	for _, obj := range items {
+		if !selected[obj] {
+			out = append(out, obj)
+			continue
+		}
+		if first && len(groups) == 0 {
+			// All the selected items are deleted: the added items take the slot of the first one.
+			out = append(out, added...)
+			added = nil
+		}
+		first = false
+		// The slots of the deleted items are left empty.
+		if seen[obj] {
+			out = append(out, groups[slot]...)
+			slot++
+		}
+	}
+	// Nothing was selected: the added items go last.
+	return append(out, added...)
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var scopeInput = []byte(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: c1
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: d1
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: c2
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: d2
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: c3
+`)
+
+func newConfigMap(t *testing.T, name string) *KubeObject {
+	obj, err := ParseKubeObject([]byte(fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", name)))
+	assert.NoError(t, err)
+	return obj
+}
+
+func TestScope(t *testing.T) {
+	testcases := []struct {
+		name     string
+		selector func(obj *KubeObject) bool
+		process  func(t *testing.T, rl *ResourceList)
+		expected []string
+	}{
+		{
+			name:     "unchanged",
+			process:  func(t *testing.T, rl *ResourceList) {},
+			expected: []string{"c1", "d1", "c2", "d2", "c3"},
+		},
+		{
+			name: "modified",
+			process: func(t *testing.T, rl *ResourceList) {
+				assert.NoError(t, rl.Items[1].SetName("c2-modified"))
+			},
+			expected: []string{"c1", "d1", "c2-modified", "d2", "c3"},
+		},
+		{
+			name: "deleted",
+			process: func(t *testing.T, rl *ResourceList) {
+				rl.Items = slices.Delete(rl.Items, 1, 2)
+			},
+			expected: []string{"c1", "d1", "d2", "c3"},
+		},
+		{
+			name: "added in the middle",
+			process: func(t *testing.T, rl *ResourceList) {
+				rl.Items = slices.Insert(rl.Items, 1, newConfigMap(t, "new"))
+			},
+			expected: []string{"c1", "new", "d1", "c2", "d2", "c3"},
+		},
+		{
+			name: "added first and last",
+			process: func(t *testing.T, rl *ResourceList) {
+				rl.Items = append(KubeObjects{newConfigMap(t, "first")}, append(rl.Items, newConfigMap(t, "last"))...)
+			},
+			expected: []string{"first", "c1", "d1", "c2", "d2", "c3", "last"},
+		},
+		{
+			name: "reordered",
+			process: func(t *testing.T, rl *ResourceList) {
+				slices.Reverse(rl.Items)
+			},
+			expected: []string{"c3", "d1", "c2", "d2", "c1"},
+		},
+		{
+			name: "deleted and reordered",
+			process: func(t *testing.T, rl *ResourceList) {
+				rl.Items = KubeObjects{rl.Items[2], rl.Items[1]}
+			},
+			expected: []string{"d1", "c3", "d2", "c2"},
+		},
+		{
+			name: "replaced",
+			process: func(t *testing.T, rl *ResourceList) {
+				rl.Items = KubeObjects{newConfigMap(t, "new")}
+			},
+			expected: []string{"new", "d1", "d2"},
+		},
+		{
+			name:     "nothing selected",
+			selector: IsGVK("", "v1", "Secret"),
+			process: func(t *testing.T, rl *ResourceList) {
+				assert.Empty(t, rl.Items)
+				rl.Items = append(rl.Items, newConfigMap(t, "new"))
+			},
+			expected: []string{"c1", "d1", "c2", "d2", "c3", "new"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rl, err := ParseResourceList(scopeInput)
+			assert.NoError(t, err)
+			rl.Results = Results{GeneralResult("previous", Info)}
+			selector := tc.selector
+			if selector == nil {
+				selector = IsGVK("", "v1", "ConfigMap")
+			}
+			p := ResourceListProcessorFunc(func(view *ResourceList) (bool, error) {
+				for _, obj := range view.Items {
+					assert.True(t, selector(obj))
+				}
+				tc.process(t, view)
+				view.Results.Infof("processed")
+				return true, nil
+			})
+
+			success, err := Use(p, Scope(selector)).Process(rl)
+			assert.True(t, success)
+			assert.NoError(t, err)
+			var names []string
+			for _, obj := range rl.Items {
+				names = append(names, obj.GetName())
+			}
+			assert.Equal(t, tc.expected, names)
+			assert.Equal(t, Results{GeneralResult("previous", Info), GeneralResult("processed", Info)}, rl.Results)
+		})
+	}
+}
+
+func TestApplyFnBySelectorResults(t *testing.T) {
+	rl, err := ParseResourceList(scopeInput)
+	assert.NoError(t, err)
+	rl.Results = Results{GeneralResult("previous", Info)}
+
+	err = ApplyFnBySelector(rl, IsGVK("apps", "v1", "Deployment"), func(obj *KubeObject) error {
+		return ConfigObjectResult("invalid", obj, Error)
+	})
+	assert.Equal(t, Results{
+		ConfigObjectResult("invalid", rl.Items[1], Error),
+		ConfigObjectResult("invalid", rl.Items[3], Error),
+	}, err)
+	assert.Equal(t, Results{
+		GeneralResult("previous", Info),
+		ConfigObjectResult("invalid", rl.Items[1], Error),
+		ConfigObjectResult("invalid", rl.Items[3], Error),
+	}, rl.Results)
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRequestBytes is the default size limit of the ResourceList POSTed to Serve.
+	DefaultMaxRequestBytes = 32 << 20
+	// DefaultRequestTimeout is the default time limit of a Serve evaluation.
+	DefaultRequestTimeout = time.Minute
+)
+
+// ServerOption configures Serve and NewHandler.
+type ServerOption func(s *server)
+
+// WithMaxRequestBytes sets the size limit of the ResourceList request body. The larger requests are rejected with
+// status 413 (Request Entity Too Large).
+func WithMaxRequestBytes(n int64) ServerOption {
+	return func(s *server) {
+		s.maxRequestBytes = n
+	}
+}
+
+// WithRequestTimeout sets the time limit of an evaluation. The deadline is carried by ResourceList.Context and by the
+// Context given to the Runner (or Generator). The evaluations that run longer are answered with status 504
+// (Gateway Timeout).
+func WithRequestTimeout(d time.Duration) ServerOption {
+	return func(s *server) {
+		s.requestTimeout = d
+	}
+}
+
+// Serve listens on the TCP network address addr and evaluates the ResourceLists POSTed to it with p. See NewHandler.
+func Serve(addr string, p ResourceListProcessor, opts ...ServerOption) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           NewHandler(p, opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+// NewHandler returns the http.Handler of Serve:
+//   - `POST /` evaluates the ResourceList (in YAML or JSON) of the request body with p, the same way as AsMain does,
+//     and responds with the ResourceList output in the same format. The status is 200 (OK) if the function
+//     succeeds, 422 (Unprocessable Entity) if it fails, and 400 (Bad Request) if the ResourceList cannot be read.
+//   - `GET /healthz` and `GET /readyz` are the liveness and readiness probes.
+//
+// The requests are evaluated concurrently, see Concurrent.
+func NewHandler(p ResourceListProcessor, opts ...ServerOption) http.Handler {
+	s := &server{
+		processor:       Concurrent(p),
+		maxRequestBytes: DefaultMaxRequestBytes,
+		requestTimeout:  DefaultRequestTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /", s.process)
+	mux.HandleFunc("GET /healthz", probe)
+	mux.HandleFunc("GET /readyz", probe)
+	return mux
+}
+
+type server struct {
+	processor       ResourceListProcessor
+	maxRequestBytes int64
+	requestTimeout  time.Duration
+}
+
+// evaluation is the output of a ResourceList evaluation.
+type evaluation struct {
+	out []byte
+	err error
+}
+
+func (s *server) process(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, s.maxRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("the ResourceList is larger than %d bytes", maxBytesErr.Limit),
+				http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), s.requestTimeout)
+	defer cancel()
+	done := make(chan evaluation, 1)
+	go func() {
+		var out bytes.Buffer
+		err := ExecuteContext(ctx, s.processor, bytes.NewReader(body), &out)
+		done <- evaluation{out: out.Bytes(), err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// The client is gone if the request is cancelled: there is nobody to respond to.
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("the function did not finish within %v", s.requestTimeout),
+				http.StatusGatewayTimeout)
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+		// The evaluation is cancelled too: wait for it to stop, so that it does not outlive the request (e.g. on
+		// http.Server.Shutdown). A processor which ignores the cancellation is given up after cancelGracePeriod.
+		select {
+		case <-done:
+		case <-time.After(cancelGracePeriod):
+		}
+	case e := <-done:
+		if len(e.out) == 0 && e.err != nil {
+			http.Error(w, e.err.Error(), http.StatusBadRequest)
+			return
+		}
+		contentType := "application/yaml"
+		if isJSON(e.out) {
+			contentType = "application/json"
+		}
+		w.Header().Set("Content-Type", contentType)
+		if e.err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		_, _ = w.Write(e.out)
+	}
+}
+
+func probe(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("ok"))
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// DeadlineReporter reports whether its Context has a deadline.
+type DeadlineReporter struct{}
+
+func (*DeadlineReporter) Run(ctx *Context, _ *KubeObject, _ KubeObjects, results *Results) bool {
+	_, hasDeadline := ctx.Deadline()
+	results.Infof("deadline: %v", hasDeadline)
+	return true
+}
+
+// LabelsReporter reports the labels decoded from the functionConfig to its map, which the caller initializes.
+type LabelsReporter struct {
+	Labels map[string]string
+}
+
+func (r *LabelsReporter) Run(_ *Context, _ *KubeObject, _ KubeObjects, results *Results) bool {
+	results.Infof("labels: %v", r.Labels)
+	return true
+}
+
+func waitForCancel(rl *ResourceList) (bool, error) {
+	<-rl.Context().Done()
+	return false, rl.Context().Err()
+}
+
+func TestServe(t *testing.T) {
+	failing := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		rl.Results.Errorf("invalid")
+		return false, nil
+	})
+	testcases := map[string]struct {
+		processor      ResourceListProcessor
+		opts           []ServerOption
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"evaluates the ResourceList as Execute": {
+			processor:      labelMiddleware{next: &removeAll{}},
+			method:         http.MethodPost,
+			path:           "/",
+			body:           runInput,
+			expectedStatus: http.StatusOK,
+		},
+		"accepts JSON": {
+			processor:      labelMiddleware{next: &removeAll{}},
+			method:         http.MethodPost,
+			path:           "/",
+			body:           `{"apiVersion": "config.kubernetes.io/v1", "kind": "ResourceList", "items": []}`,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+  "apiVersion": "config.kubernetes.io/v1",
+  "kind": "ResourceList",
+  "items": [],
+  "results": [
+    {
+      "message": "wrapped",
+      "severity": "info"
+    }
+  ]
+}
+`,
+		},
+		"carries the deadline to the Runner": {
+			processor:      WithContext(context.Background(), &DeadlineReporter{}),
+			method:         http.MethodPost,
+			path:           "/",
+			body:           runInput,
+			expectedStatus: http.StatusOK,
+			expectedBody: `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+    annotations:
+      internal.config.kubernetes.io/path: cm.yaml
+results:
+- message: from a previous function
+  severity: info
+- message: '` + "`FunctionConfig`" + ` is not given'
+  severity: info
+- message: 'deadline: true'
+  severity: info
+`,
+		},
+		"function failure": {
+			processor:      failing,
+			method:         http.MethodPost,
+			path:           "/",
+			body:           runInput,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		"invalid ResourceList": {
+			processor:      failing,
+			method:         http.MethodPost,
+			path:           "/",
+			body:           "- not a ResourceList",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"request too large": {
+			processor:      failing,
+			opts:           []ServerOption{WithMaxRequestBytes(10)},
+			method:         http.MethodPost,
+			path:           "/",
+			body:           runInput,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "the ResourceList is larger than 10 bytes\n",
+		},
+		"timeout": {
+			processor:      ResourceListProcessorFunc(waitForCancel),
+			opts:           []ServerOption{WithRequestTimeout(10 * time.Millisecond)},
+			method:         http.MethodPost,
+			path:           "/",
+			body:           runInput,
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "the function did not finish within 10ms\n",
+		},
+		"liveness": {
+			method:         http.MethodGet,
+			path:           "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"readiness": {
+			method:         http.MethodGet,
+			path:           "/readyz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+	}
+	for name, test := range testcases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(NewHandler(test.processor, test.opts...))
+			defer srv.Close()
+
+			req, err := http.NewRequest(test.method, srv.URL+test.path, strings.NewReader(test.body))
+			assert.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			expectedBody := test.expectedBody
+			if expectedBody == "" && test.expectedStatus != http.StatusBadRequest {
+				var out bytes.Buffer
+				_ = Execute(test.processor, strings.NewReader(test.body), &out)
+				expectedBody = out.String()
+			}
+			if expectedBody != "" {
+				assert.Equal(t, expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestServeCancel(t *testing.T) {
+	stopped := make(chan struct{})
+	waiting := make(chan struct{})
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		close(waiting)
+		defer close(stopped)
+		return waitForCancel(rl)
+	})
+	testcases := map[string]struct {
+		opts           []ServerOption
+		cancel         bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		"client disconnected": {
+			cancel:         true,
+			expectedStatus: http.StatusOK,
+		},
+		"timeout": {
+			opts:           []ServerOption{WithRequestTimeout(10 * time.Millisecond)},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "the function did not finish within 10ms\n",
+		},
+	}
+	for name, test := range testcases {
+		t.Run(name, func(t *testing.T) {
+			stopped, waiting = make(chan struct{}), make(chan struct{})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(runInput)).WithContext(ctx)
+			if test.cancel {
+				go func() {
+					<-waiting
+					cancel()
+				}()
+			}
+			w := httptest.NewRecorder()
+			NewHandler(p, test.opts...).ServeHTTP(w, req)
+
+			// The handler returns once the evaluation has stopped.
+			select {
+			case <-stopped:
+			default:
+				t.Error("the evaluation is still running")
+			}
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestServeIgnoredCancel(t *testing.T) {
+	gracePeriod := cancelGracePeriod
+	cancelGracePeriod = 10 * time.Millisecond
+	defer func() { cancelGracePeriod = gracePeriod }()
+	release := make(chan struct{})
+	defer close(release)
+	p := ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		<-release
+		return true, nil
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(runInput))
+	NewHandler(p, WithRequestTimeout(10*time.Millisecond)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestServeConcurrentRunner(t *testing.T) {
+	runner := &LabelsReporter{Labels: map[string]string{"owner": "platform"}}
+	server := httptest.NewServer(NewHandler(WithContext(context.Background(), runner)))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+  data:
+    team: team-%d
+`, i)
+			resp, err := http.Post(server.URL, "application/yaml", strings.NewReader(body))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+			out, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			// Each evaluation sees the initial labels and its own functionConfig only.
+			assert.Contains(t, string(out), fmt.Sprintf("'labels: map[owner:platform team:team-%d]'", i))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]string{"owner": "platform"}, runner.Labels)
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceFileEnv is the environment variable of the file the traces of Run, Execute and AsMain are appended to. Each
+// evaluation appends a JSON line with its spans (Run or Execute, and the nested Chain, ChainFunctions and
+// ApplyFnBySelector calls) and its metrics:
+//
+//	{"traceId":"...","spans":[{"spanId":"...","parentSpanId":"...","name":"Chain[0]","startTimeUnixNano":...,
+//	"endTimeUnixNano":...,"attributes":{"processor":"..."}}],"metrics":{"bytes.parsed":1024,"items.processed":3,
+//	"results.error":1}}
+//
+// Nothing is traced if it is unset.
+const TraceFileEnv = "KRM_FN_TRACE_FILE"
+
+const (
+	// metricBytesParsed is the size of the ResourceList input.
+	metricBytesParsed = "bytes.parsed"
+	// metricItemsProcessed is the number of items the functions of ApplyFnBySelector are applied to.
+	metricItemsProcessed = "items.processed"
+	// metricResultsPrefix prefixes the number of results of each severity.
+	metricResultsPrefix = "results."
+)
+
+// trace collects the spans and the metrics of an evaluation.
+type trace struct {
+	file string
+	id   string
+	mu   sync.Mutex
+	// spans are the ended spans.
+	spans   []*span
+	metrics map[string]int64
+	nextID  int
+}
+
+// span is a timed operation of a trace. A nil span is a no-op, so that the evaluations cost nothing if they are not
+// traced.
+type span struct {
+	trace  *trace
+	parent *span
+	id     string
+	name   string
+	start  time.Time
+	end    time.Time
+	attrs  map[string]any
+	err    error
+}
+
+// startTrace returns the root span of the trace of the evaluation `name`, or nil if the TraceFileEnv is unset.
+func startTrace(name string) *span {
+	file := os.Getenv(TraceFileEnv)
+	if file == "" {
+		return nil
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	t := &trace{file: file, id: hex.EncodeToString(id), metrics: map[string]int64{}}
+	return t.newSpan(nil, name)
+}
+
+func (t *trace) newSpan(parent *span, name string) *span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	return &span{trace: t, parent: parent, id: fmt.Sprintf("%016x", t.nextID), name: name, start: time.Now()}
+}
+
+// child starts a span in the span.
+func (s *span) child(name string) *span {
+	if s == nil {
+		return nil
+	}
+	return s.trace.newSpan(s, name)
+}
+
+// set sets an attribute of the span.
+func (s *span) set(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+	if s.attrs == nil {
+		s.attrs = map[string]any{}
+	}
+	s.attrs[key] = value
+}
+
+// count adds n to the metric of the trace.
+func (s *span) count(metric string, n int) {
+	if s == nil {
+		return
+	}
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+	s.trace.metrics[metric] += int64(n)
+}
+
+// finish ends the span with the error of the operation, if any.
+func (s *span) finish(err error) {
+	if s == nil {
+		return
+	}
+	s.trace.mu.Lock()
+	defer s.trace.mu.Unlock()
+	s.end = time.Now()
+	s.err = err
+	s.trace.spans = append(s.trace.spans, s)
+}
+
+// startSpan starts a span in the current span of the ResourceList, and makes it the current span until endSpan.
+func startSpan(rl *ResourceList, name string) *span {
+	s := rl.span.child(name)
+	if s != nil {
+		rl.span = s
+	}
+	return s
+}
+
+// endSpan ends the span started by startSpan, and makes its parent the current span of the ResourceList again.
+func endSpan(rl *ResourceList, s *span, err error) {
+	if s == nil {
+		return
+	}
+	s.finish(err)
+	rl.span = s.parent
+}
+
+// endTrace ends the root span of the trace with the metrics of the ResourceList, and appends the trace to the
+// TraceFileEnv file. A trace that cannot be written is logged, since it must not fail the function.
+func endTrace(root *span, rl *ResourceList, err error) {
+	if root == nil {
+		return
+	}
+	if rl != nil {
+		root.set("items", len(rl.Items))
+		root.count(metricBytesParsed, len(rl.input))
+		for _, result := range rl.Results {
+			if result != nil {
+				root.count(metricResultsPrefix+strings.ToLower(string(severityOf(result))), 1)
+			}
+		}
+	}
+	root.finish(err)
+	if writeErr := root.trace.write(); writeErr != nil {
+		Logf("failed to write the trace to %v: %v\n", root.trace.file, writeErr)
+	}
+}
+
+type traceJSON struct {
+	TraceID string           `json:"traceId"`
+	Spans   []spanJSON       `json:"spans"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+type spanJSON struct {
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano int64          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   int64          `json:"endTimeUnixNano"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	Error             string         `json:"error,omitempty"`
+}
+
+func (t *trace) write() error {
+	t.mu.Lock()
+	out := traceJSON{TraceID: t.id, Metrics: t.metrics}
+	for _, s := range t.spans {
+		j := spanJSON{
+			SpanID:            s.id,
+			Name:              s.name,
+			StartTimeUnixNano: s.start.UnixNano(),
+			EndTimeUnixNano:   s.end.UnixNano(),
+			Attributes:        s.attrs,
+		}
+		if s.parent != nil {
+			j.ParentSpanID = s.parent.id
+		}
+		if s.err != nil {
+			j.Error = s.err.Error()
+		}
+		out.Spans = append(out.Spans, j)
+	}
+	b, err := json.Marshal(out)
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(t.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// traceStep processes the step `index` of the chain in a span, if the ResourceList is traced.
+func traceStep(rl *ResourceList, chain string, index int, step any,
+	process func(rl *ResourceList) (bool, error)) (success bool, err error) {
+	if rl.span == nil {
+		return process(rl)
+	}
+	span := startSpan(rl, fmt.Sprintf("%s[%d]", chain, index))
+	span.set("step", stepName(step))
+	defer func() {
+		span.set("success", success)
+		endSpan(rl, span, err)
+	}()
+	return process(rl)
+}
+
+// stepName returns the name of the function, or the type of the processor.
+func stepName(step any) string {
+	if v := reflect.ValueOf(step); v.Kind() == reflect.Func {
+		if f := runtime.FuncForPC(v.Pointer()); f != nil {
+			return f.Name()
+		}
+	}
+	return fmt.Sprintf("%T", step)
+}
+
+// traceProcess processes the ResourceList with p (see process) in a span, if the ResourceList is traced.
+func traceProcess(p ResourceListProcessor, rl *ResourceList) (success bool, err error) {
+	span := startSpan(rl, "process")
+	defer func() {
+		span.set("success", success)
+		endSpan(rl, span, err)
+	}()
+	return process(p, rl)
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var traceInput = []byte(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: second
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: nginx
+`)
+
+func setLabels(rl *ResourceList) (bool, error) {
+	return true, ApplyFnBySelector(rl, IsGVK("", "v1", "ConfigMap"), func(obj *KubeObject) error {
+		return obj.SetLabel("traced", "true")
+	})
+}
+
+func warnDeployments(rl *ResourceList) (bool, error) {
+	for _, obj := range rl.Items.Where(IsGVK("apps", "v1", "Deployment")) {
+		rl.Results = append(rl.Results, ConfigObjectResult("no replicas", obj, Warning))
+	}
+	return true, nil
+}
+
+func readTraces(t *testing.T, file string) []traceJSON {
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	var traces []traceJSON
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var trace traceJSON
+		assert.NoError(t, json.Unmarshal([]byte(line), &trace))
+		traces = append(traces, trace)
+	}
+	return traces
+}
+
+// spanTree returns the names of the spans, prefixed by the names of their parents.
+func spanTree(trace traceJSON) []string {
+	names := map[string]string{}
+	parents := map[string]string{}
+	for _, span := range trace.Spans {
+		names[span.SpanID] = span.Name
+		parents[span.SpanID] = span.ParentSpanID
+	}
+	var tree []string
+	for _, span := range trace.Spans {
+		path := span.Name
+		for parent := span.ParentSpanID; parent != ""; parent = parents[parent] {
+			path = names[parent] + "/" + path
+		}
+		tree = append(tree, path)
+	}
+	return tree
+}
+
+func TestTrace(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trace.jsonl")
+	t.Setenv(TraceFileEnv, file)
+
+	p := Chain(ResourceListProcessorFunc(setLabels), ChainFunctions(warnDeployments))
+	_, err := Run(p, traceInput)
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	assert.NoError(t, Execute(p, bytes.NewReader(traceInput), &out))
+
+	traces := readTraces(t, file)
+	if !assert.Len(t, traces, 2) {
+		return
+	}
+	assert.ElementsMatch(t, []string{
+		"Run/parse",
+		"Run/process/Chain/Chain[0]/ApplyFnBySelector",
+		"Run/process/Chain/Chain[0]",
+		"Run/process/Chain/Chain[1]/ChainFunctions/ChainFunctions[0]",
+		"Run/process/Chain/Chain[1]/ChainFunctions",
+		"Run/process/Chain/Chain[1]",
+		"Run/process/Chain",
+		"Run/process",
+		"Run",
+	}, spanTree(traces[0]))
+	assert.ElementsMatch(t, []string{
+		"Execute/read",
+		"Execute/process/Chain/Chain[0]/ApplyFnBySelector",
+		"Execute/process/Chain/Chain[0]",
+		"Execute/process/Chain/Chain[1]/ChainFunctions/ChainFunctions[0]",
+		"Execute/process/Chain/Chain[1]/ChainFunctions",
+		"Execute/process/Chain/Chain[1]",
+		"Execute/process/Chain",
+		"Execute/process",
+		"Execute/write",
+		"Execute",
+	}, spanTree(traces[1]))
+
+	for _, trace := range traces {
+		assert.Len(t, trace.TraceID, 32)
+		assert.Equal(t, map[string]int64{
+			metricBytesParsed:    int64(len(traceInput)),
+			metricItemsProcessed: 2,
+			"results.warning":    1,
+		}, trace.Metrics)
+		for _, span := range trace.Spans {
+			assert.LessOrEqual(t, span.StartTimeUnixNano, span.EndTimeUnixNano)
+			switch span.Name {
+			case "Chain[0]":
+				assert.Equal(t, map[string]any{"step": "github.com/kptdev/krm-functions-sdk/go/fn.setLabels",
+					"success": true}, span.Attributes)
+			case "ApplyFnBySelector":
+				assert.Equal(t, map[string]any{"selected": float64(2)}, span.Attributes)
+			case "Run", "Execute":
+				assert.Equal(t, map[string]any{"items": float64(3)}, span.Attributes)
+			}
+		}
+	}
+}
+
+func TestTraceError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trace.jsonl")
+	t.Setenv(TraceFileEnv, file)
+
+	_, err := Run(ResourceListProcessorFunc(func(rl *ResourceList) (bool, error) {
+		return false, ApplyFnBySelector(rl, func(*KubeObject) bool { return true }, func(obj *KubeObject) error {
+			return ErrorConfigObjectResult(assert.AnError, obj)
+		})
+	}), traceInput)
+	assert.Error(t, err)
+
+	traces := readTraces(t, file)
+	if !assert.Len(t, traces, 1) {
+		return
+	}
+	assert.Equal(t, map[string]int64{
+		metricBytesParsed:    int64(len(traceInput)),
+		metricItemsProcessed: 3,
+		"results.error":      3,
+	}, traces[0].Metrics)
+	for _, span := range traces[0].Spans {
+		if span.Name != "parse" {
+			assert.NotEmpty(t, span.Error, span.Name)
+		}
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	t.Setenv(TraceFileEnv, "")
+	rl, err := ParseResourceList(traceInput)
+	assert.NoError(t, err)
+	assert.Nil(t, startSpan(rl, "Chain"))
+
+	p := Chain(ResourceListProcessorFunc(warnDeployments))
+	allocs := testing.AllocsPerRun(10, func() {
+		rl.Results = nil
+		_, _ = traceStep(rl, "Chain", 0, p, p.Process)
+	})
+	// The allocations of warnDeployments only.
+	expected := testing.AllocsPerRun(10, func() {
+		rl.Results = nil
+		_, _ = p.Process(rl)
+	})
+	assert.Equal(t, expected, allocs)
+}
@@ -15,9 +15,19 @@
 // Package fn is the SDK for go krm functions.
 package fn
 
-import "sigs.k8s.io/kustomize/kyaml/kio"
+import (
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// resourceListReadWriter reads the function input and writes the function output.
+type resourceListReadWriter interface {
+	Read() (*ResourceList, error)
+	Write(rl *ResourceList) error
+}
 
 // byteReadWriter wraps kio.ByteReadWriter
 type byteReadWriter struct {
 	kio.ByteReadWriter
+	// json tells whether the input is in json format, so that the output is also written in json.
+	json bool
 }
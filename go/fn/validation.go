@@ -0,0 +1,357 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// DefaultTag is the struct tag that gives the default value of a functionConfig field. The value is converted to
+	// the field type the same way as a ConfigMap `.data` value, e.g. `kptDefault:"3"` or `kptDefault:"1m"`. The
+	// default only applies to a field which is zero and whose key is missing from the functionConfig, so that an
+	// explicit `false` or `0` is kept. The tag name is specific to the SDK, so that the `default` tags of other
+	// libraries are left alone.
+	DefaultTag = "kptDefault"
+	// ValidateTag is the struct tag that gives the comma-separated validation rules of a functionConfig field:
+	//   - required: the field must be set to a non-zero value.
+	//   - enum=a|b|c: the value must be one of the given values.
+	//   - min=N, max=N: the bounds of a number, or of the length of a string, slice or map.
+	//   - exclusive=GROUP: at most one field of the same GROUP can be set.
+	//   - pattern=REGEX: the string value must match the regular expression. As the expression may contain commas,
+	//     pattern must be the last rule.
+	//
+	// The rules other than required are only checked on the fields that are set.
+	// e.g. `kptValidate:"required,min=1,max=10"`. The tag name is specific to the SDK, so that the `validate` tags of
+	// other libraries, e.g. `validate:"gte=1"`, are left alone.
+	ValidateTag = "kptValidate"
+)
+
+// fieldRules are the parsed ValidateTag rules of a field.
+type fieldRules struct {
+	required  bool
+	enum      []string
+	min       string
+	max       string
+	pattern   *regexp.Regexp
+	exclusive string
+}
+
+func parseFieldRules(tag string) (fieldRules, error) {
+	var rules fieldRules
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "pattern=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			rules.required = true
+		case "enum":
+			rules.enum = strings.Split(value, "|")
+		case "min":
+			rules.min = value
+		case "max":
+			rules.max = value
+		case "exclusive":
+			rules.exclusive = value
+		case "pattern":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return rules, fmt.Errorf("invalid pattern %q: %w", value, err)
+			}
+			rules.pattern = re
+		case "":
+		default:
+			return rules, fmt.Errorf("unknown validation rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// defaultAndValidate sets the DefaultTag values to the unset fields of the functionConfig struct, and then checks
+// the ValidateTag rules. `path` is the field path of the struct in the functionConfig object, e.g. "data" for a
+// ConfigMap. `isSet` tells whether a field path is set in the functionConfig object; if nil, no field is. The
+// violations are returned as Results whose Field.Path points to the offending functionConfig field.
+func defaultAndValidate(config any, path string, isSet func(path string) bool) error {
+	v := reflect.ValueOf(config)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	if isSet == nil {
+		isSet = func(string) bool { return false }
+	}
+	var results Results
+	defaultAndValidateStruct(v.Elem(), path, isSet, &results)
+	if len(results) > 0 {
+		return results
+	}
+	return nil
+}
+
+func defaultAndValidateStruct(obj reflect.Value, path string, isSet func(path string) bool, results *Results) {
+	exclusiveGroups := map[string][]string{}
+	for i := 0; i < obj.NumField(); i++ {
+		sf := obj.Type().Field(i)
+		fv := obj.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+		key, hasTag := fieldKey(sf)
+		if key == "-" {
+			continue
+		}
+		if sf.Anonymous && !hasTag && fv.Kind() == reflect.Struct {
+			defaultAndValidateStruct(fv, path, isSet, results)
+			continue
+		}
+		fieldPath := joinFieldPath(path, key)
+		if def, found := sf.Tag.Lookup(DefaultTag); found && fv.IsZero() && !isSet(fieldPath) {
+			if err := setFromString(fv, def); err != nil {
+				*results = append(*results, configFieldResult(fieldPath, nil,
+					fmt.Sprintf("invalid default value %q: %v", def, err)))
+			}
+		}
+		rules, err := parseFieldRules(sf.Tag.Get(ValidateTag))
+		if err != nil {
+			*results = append(*results, configFieldResult(fieldPath, nil, err.Error()))
+			continue
+		}
+		validateField(fv, fieldPath, rules, results)
+		if rules.exclusive != "" && !fv.IsZero() {
+			exclusiveGroups[rules.exclusive] = append(exclusiveGroups[rules.exclusive], fieldPath)
+		}
+		defaultAndValidateNested(fv, fieldPath, isSet, results)
+	}
+
+	groups := make([]string, 0, len(exclusiveGroups))
+	for group := range exclusiveGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		if paths := exclusiveGroups[group]; len(paths) > 1 {
+			*results = append(*results, configFieldResult(paths[1], nil,
+				fmt.Sprintf("fields `%v` are mutually exclusive, only one of them can be set", strings.Join(paths, "`, `"))))
+		}
+	}
+}
+
+// defaultAndValidateNested walks into the struct values of a field.
+func defaultAndValidateNested(fv reflect.Value, path string, isSet func(path string) bool, results *Results) {
+	switch fv.Kind() {
+	case reflect.Struct:
+		if fv.Type() != durationType {
+			defaultAndValidateStruct(fv, path, isSet, results)
+		}
+	case reflect.Ptr:
+		if !fv.IsNil() && fv.Elem().Kind() == reflect.Struct {
+			defaultAndValidateStruct(fv.Elem(), path, isSet, results)
+		}
+	case reflect.Slice:
+		for i := 0; i < fv.Len(); i++ {
+			defaultAndValidateNested(fv.Index(i), fmt.Sprintf("%v[%d]", path, i), isSet, results)
+		}
+	}
+}
+
+// configFieldIsSet returns a function which tells whether a field path is set in the functionConfig object. The keys are matched the same
+// way as they are decoded: exactly, or else case-insensitively. The keys of a ConfigMap `data` may contain dots.
+func configFieldIsSet(o *KubeObject) func(path string) bool {
+	if o == nil || o.IsEmpty() {
+		return nil
+	}
+	root := o.obj.Node()
+	return func(path string) bool {
+		node := root
+		if o.GroupKind() == (schema.GroupKind{Kind: "ConfigMap"}) {
+			key, found := strings.CutPrefix(path, "data.")
+			if !found {
+				return false
+			}
+			data, found := lookupNodeField(node, "data")
+			return found && data.Kind == yaml.MappingNode && foldFieldIndex(data, key) >= 0
+		}
+		segments, err := parseFieldPath(path)
+		if err != nil {
+			return false
+		}
+		for _, segment := range segments {
+			if segment.key == "" {
+				if node.Kind != yaml.SequenceNode {
+					return false
+				}
+				if node = segment.find(node); node == nil {
+					return false
+				}
+				continue
+			}
+			var found bool
+			if node, found = lookupNodeField(node, segment.key); !found {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// lookupNodeField returns the value of the key of the mapping node.
+func lookupNodeField(node *yaml.Node, key string) (*yaml.Node, bool) {
+	if node.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	i := foldFieldIndex(node, key)
+	if i < 0 {
+		return nil, false
+	}
+	return node.Content[i+1], true
+}
+
+// foldFieldIndex is fieldIndex with a case-insensitive fallback.
+func foldFieldIndex(node *yaml.Node, key string) int {
+	if i := fieldIndex(node, key); i >= 0 {
+		return i
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateField(fv reflect.Value, path string, rules fieldRules, results *Results) {
+	if fv.IsZero() {
+		if rules.required {
+			*results = append(*results, configFieldResult(path, nil, "required field is missing"))
+		}
+		return
+	}
+	if fv.Kind() == reflect.Ptr {
+		fv = fv.Elem()
+	}
+	if rules.min != "" || rules.max != "" {
+		if msg, err := checkBounds(fv, rules.min, rules.max); err != nil {
+			*results = append(*results, configFieldResult(path, nil, err.Error()))
+		} else if msg != "" {
+			*results = append(*results, configFieldResult(path, fv.Interface(), msg))
+		}
+	}
+	// enum and pattern apply to the scalar value, or to each element of a slice.
+	values := []reflect.Value{fv}
+	if fv.Kind() == reflect.Slice {
+		values = nil
+		for i := 0; i < fv.Len(); i++ {
+			values = append(values, fv.Index(i))
+		}
+	}
+	for _, value := range values {
+		s := fmt.Sprint(value.Interface())
+		if len(rules.enum) > 0 && !slices.Contains(rules.enum, s) {
+			*results = append(*results, configFieldResult(path, value.Interface(),
+				fmt.Sprintf("value %q is not one of [%v]", s, strings.Join(rules.enum, ", "))))
+		}
+		if rules.pattern != nil && !rules.pattern.MatchString(s) {
+			*results = append(*results, configFieldResult(path, value.Interface(),
+				fmt.Sprintf("value %q does not match pattern %q", s, rules.pattern.String())))
+		}
+	}
+}
+
+// checkBounds checks the min and max rules. It returns an error if the rules themselves are invalid, and a non-empty
+// message if the value is out of bounds.
+func checkBounds(fv reflect.Value, minRule, maxRule string) (string, error) {
+	var value float64
+	var parse func(string) (float64, error)
+	subject := "value"
+	switch {
+	case fv.Type() == durationType:
+		value = float64(fv.Int())
+		parse = func(s string) (float64, error) {
+			d, err := time.ParseDuration(s)
+			return float64(d), err
+		}
+	case fv.CanInt():
+		value = float64(fv.Int())
+	case fv.CanUint():
+		value = float64(fv.Uint())
+	case fv.CanFloat():
+		value = fv.Float()
+	case fv.Kind() == reflect.String || fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map:
+		value = float64(fv.Len())
+		subject = "length"
+	default:
+		return "", fmt.Errorf("min and max are not supported for type %v", fv.Type())
+	}
+	if parse == nil {
+		parse = func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		}
+	}
+	if minRule != "" {
+		bound, err := parse(minRule)
+		if err != nil {
+			return "", fmt.Errorf("invalid min %q: %w", minRule, err)
+		}
+		if value < bound {
+			return fmt.Sprintf("%v %v is less than the minimum %v", subject, formatBound(fv, value, subject), minRule), nil
+		}
+	}
+	if maxRule != "" {
+		bound, err := parse(maxRule)
+		if err != nil {
+			return "", fmt.Errorf("invalid max %q: %w", maxRule, err)
+		}
+		if value > bound {
+			return fmt.Sprintf("%v %v is greater than the maximum %v", subject, formatBound(fv, value, subject), maxRule), nil
+		}
+	}
+	return "", nil
+}
+
+func formatBound(fv reflect.Value, value float64, subject string) string {
+	if subject == "value" {
+		return fmt.Sprint(fv.Interface())
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func configFieldResult(path string, currentValue any, msg string) *Result {
+	return &Result{
+		Message:  msg,
+		Severity: Error,
+		Field:    &Field{Path: path, CurrentValue: currentValue},
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ValidatedConfig struct {
+	Name     string        `json:"name,omitempty" kptValidate:"required,pattern=^[a-z]+(-[a-z]+)*$"`
+	Mode     string        `json:"mode,omitempty" kptDefault:"merge" kptValidate:"enum=merge|replace"`
+	Replicas int           `json:"replicas,omitempty" kptDefault:"1" kptValidate:"min=1,max=5"`
+	Timeout  time.Duration `json:"timeout,omitempty" kptDefault:"30s" kptValidate:"max=1m"`
+	Tags     []string      `json:"tags,omitempty" kptValidate:"max=2"`
+	Image    string        `json:"image,omitempty" kptValidate:"exclusive=source"`
+	Build    *BuildSpec    `json:"build,omitempty" kptValidate:"exclusive=source"`
+
+	ran bool
+}
+
+type BuildSpec struct {
+	Context string `json:"context,omitempty" kptValidate:"required"`
+}
+
+func (c *ValidatedConfig) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	c.ran = true
+	return true
+}
+
+func TestDefaultAndValidate(t *testing.T) {
+	testdata := map[string]struct {
+		fnConfig    string
+		expected    *ValidatedConfig
+		expectedErr string
+	}{
+		"defaults are applied": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ValidatedConfig
+metadata:
+  name: test
+name: my-app
+replicas: 3
+`,
+			expected: &ValidatedConfig{Name: "my-app", Mode: "merge", Replicas: 3, Timeout: 30 * time.Second, ran: true},
+		},
+		"explicit zero values are kept": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ValidatedConfig
+metadata:
+  name: test
+name: my-app
+mode: ""
+replicas: 0
+`,
+			expected: &ValidatedConfig{Name: "my-app", Timeout: 30 * time.Second, ran: true},
+		},
+		"explicit zero values are kept in ConfigMap": {
+			fnConfig: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  name: my-app
+  Replicas: "0"
+`,
+			expected: &ValidatedConfig{Name: "my-app", Mode: "merge", Timeout: 30 * time.Second, ran: true},
+		},
+		"defaults and validation from ConfigMap": {
+			fnConfig: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  name: my-app
+  mode: replace
+  replicas: "6"
+  timeout: 2m
+`,
+			expectedErr: "[error] data.replicas: value 6 is greater than the maximum 5\n---\n" +
+				"[error] data.timeout: value 2m0s is greater than the maximum 1m",
+		},
+		"violations are reported with the field path": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ValidatedConfig
+metadata:
+  name: test
+mode: patch
+tags: [a, b, c]
+image: nginx
+build: {}
+`,
+			expectedErr: "[error] name: required field is missing\n---\n" +
+				"[error] mode: value \"patch\" is not one of [merge, replace]\n---\n" +
+				"[error] tags: length 3 is greater than the maximum 2\n---\n" +
+				"[error] build.context: required field is missing\n---\n" +
+				"[error] build: fields `image`, `build` are mutually exclusive, only one of them can be set",
+		},
+		"pattern": {
+			fnConfig: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ValidatedConfig
+metadata:
+  name: test
+name: My_App
+`,
+			expectedErr: "[error] name: value \"My_App\" does not match pattern \"^[a-z]+(-[a-z]+)*$\"",
+		},
+	}
+	for description, test := range testdata {
+		config := &ValidatedConfig{}
+		fnConfig, err := ParseKubeObject([]byte(test.fnConfig))
+		assert.NoError(t, err, description)
+		rl := &ResourceList{FunctionConfig: fnConfig}
+		ok, err := WithContext(context.TODO(), config).Process(rl)
+		assert.NoError(t, err, description)
+		if test.expectedErr != "" {
+			assert.False(t, ok, description)
+			assert.False(t, config.ran, "the function should not run: "+description)
+			assert.Equal(t, test.expectedErr, rl.Results.String(), description)
+			continue
+		}
+		assert.True(t, ok, description)
+		assert.Equal(t, test.expected, config, description)
+	}
+}
+
+func TestParseFieldRules(t *testing.T) {
+	rules, err := parseFieldRules("required,enum=a|b,pattern=^(a|b),c$")
+	assert.NoError(t, err)
+	assert.True(t, rules.required)
+	assert.Equal(t, []string{"a", "b"}, rules.enum)
+	assert.Equal(t, "^(a|b),c$", rules.pattern.String())
+
+	_, err = parseFieldRules("required,unique")
+	assert.EqualError(t, err, "unknown validation rule \"unique\"")
+}
+
+// ForeignTagsConfig has the tags of other validation and default libraries.
+type ForeignTagsConfig struct {
+	Replicas int    `json:"replicas,omitempty" validate:"gte=1" default:"3"`
+	Mode     string `json:"mode,omitempty" kptDefault:"merge"`
+
+	ran bool
+}
+
+func (c *ForeignTagsConfig) Run(*Context, *KubeObject, KubeObjects, *Results) bool {
+	c.ran = true
+	return true
+}
+
+func TestForeignTags(t *testing.T) {
+	fnConfig, err := ParseKubeObject([]byte(`
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ForeignTagsConfig
+metadata:
+  name: test
+`))
+	assert.NoError(t, err)
+	config := &ForeignTagsConfig{}
+	ok, err := WithContext(context.TODO(), config).Process(&ResourceList{FunctionConfig: fnConfig})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Only the tags of the SDK are applied.
+	assert.Equal(t, &ForeignTagsConfig{Mode: "merge", ran: true}, config)
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+var execCmdOutputFn = execCmdOutput
+
+const (
+	// CRDGeneratorFile is the temporary test file that kfn adds to the function package to generate the CRD.
+	CRDGeneratorFile = "zz_kfn_crd_test.go"
+	// CRDGeneratorTest is the test in CRDGeneratorFile.
+	CRDGeneratorTest = "TestKfnGenerateCRD"
+	// CRDOutputEnvVar tells CRDGeneratorTest where to write the CRD.
+	CRDOutputEnvVar = "KFN_CRD_OUTPUT"
+)
+
+var crdGeneratorTemplate = template.Must(template.New("crd").Parse(`// Code generated by kfn. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kptdev/krm-functions-sdk/go/fn"
+)
+
+func {{ .Test }}(t *testing.T) {
+	docs, err := fn.ParseFieldDocs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd, err := fn.ConfigCRD(&{{ .Type }}{}, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(os.Getenv("{{ .OutputEnvVar }}"), []byte(crd.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+`))
+
+func NewCRDRunner(ctx context.Context) *CRDRunner {
+	r := &CRDRunner{
+		ctx: ctx,
+	}
+	r.Command = &cobra.Command{
+		Use:   "crd [DIR]",
+		Short: "generate the CustomResourceDefinition of your KRM function config",
+		Long: "generate the CustomResourceDefinition of the functionConfig from the Runner struct of the function " +
+			"in DIR (default to the current directory). The schema honors the json tags, the doc comments and the " +
+			"`kptDefault` and `kptValidate` tags of the struct fields.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: r.RunE,
+	}
+	r.Command.Flags().StringVarP(&r.TypeName, "type", "t", "",
+		"the Runner struct name. If not given, kfn looks for the struct which implements `Run` or `Generate`")
+	r.Command.Flags().StringVarP(&r.Output, "output", "o", "",
+		"the file to write the CRD to. If not given, the CRD is written to STDOUT")
+	return r
+}
+
+// CRDRunner generates the functionConfig CustomResourceDefinition of a KRM function project.
+type CRDRunner struct {
+	ctx     context.Context
+	Command *cobra.Command
+
+	Dir      string
+	TypeName string
+	Output   string
+}
+
+func (r *CRDRunner) RunE(cmd *cobra.Command, args []string) error {
+	r.Dir = "."
+	if len(args) == 1 {
+		r.Dir = args[0]
+	}
+	pkgName, runnerTypes, err := FindRunnerTypes(r.Dir)
+	if err != nil {
+		return err
+	}
+	if r.TypeName == "" {
+		if len(runnerTypes) != 1 {
+			return fmt.Errorf("found %d Runner structs %v in %v, please specify one by `--type`",
+				len(runnerTypes), runnerTypes, r.Dir)
+		}
+		r.TypeName = runnerTypes[0]
+	}
+
+	var src bytes.Buffer
+	if err = crdGeneratorTemplate.Execute(&src, map[string]string{
+		"Package":      pkgName,
+		"Test":         CRDGeneratorTest,
+		"Type":         r.TypeName,
+		"OutputEnvVar": CRDOutputEnvVar,
+	}); err != nil {
+		return err
+	}
+	generatorPath := filepath.Join(r.Dir, CRDGeneratorFile)
+	if err = os.WriteFile(generatorPath, src.Bytes(), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(generatorPath)
+
+	tmp, err := os.CreateTemp("", "kfn-crd-*.yaml")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	envs := []string{CRDOutputEnvVar + "=" + tmp.Name()}
+	out, err := execCmdOutputFn(envs, "go", "-C", r.Dir, "test", "-count=1", "-run", "^"+CRDGeneratorTest+"$", ".")
+	if err != nil {
+		return fmt.Errorf("failed to generate the CRD: %w\n%s", err, out)
+	}
+	crd, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if r.Output == "" {
+		_, err = cmd.OutOrStdout().Write(crd)
+		return err
+	}
+	return os.WriteFile(r.Output, crd, 0644)
+}
+
+// FindRunnerTypes returns the package name of the Go files in dir, and the names of the structs which have a `Run` or
+// `Generate` method of four arguments, which is the signature of fn.Runner and fn.Generator.
+func FindRunnerTypes(dir string) (string, []string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	var pkgName string
+	var runnerTypes []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse %v: %w", file, err)
+		}
+		pkgName = f.Name.Name
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+				continue
+			}
+			if fd.Name.Name != "Run" && fd.Name.Name != "Generate" {
+				continue
+			}
+			if fd.Type.Params.NumFields() != 4 {
+				continue
+			}
+			recv := fd.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				runnerTypes = append(runnerTypes, ident.Name)
+			}
+		}
+	}
+	if pkgName == "" {
+		return "", nil, fmt.Errorf("no Go files found in %v", dir)
+	}
+	return pkgName, runnerTypes, nil
+}
+
+func execCmdOutput(envs []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if len(envs) != 0 {
+		cmd.Env = os.Environ()
+		cmd.Env = append(cmd.Env, envs...)
+	}
+	return cmd.CombinedOutput()
+}
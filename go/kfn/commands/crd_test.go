@@ -0,0 +1,99 @@
+// Copyright 2026 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const crdTestFunction = `package main
+
+import "github.com/kptdev/krm-functions-sdk/go/fn"
+
+type SetLabels struct {
+	Labels map[string]string ` + "`json:\"labels\"`" + `
+}
+
+func (r *SetLabels) Run(ctx *fn.Context, functionConfig *fn.KubeObject, items fn.KubeObjects, results *fn.Results) bool {
+	return true
+}
+
+type helper struct{}
+
+func (h *helper) Run() {}
+`
+
+func TestFindRunnerTypes(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(crdTestFunction), 0600))
+
+	pkgName, runnerTypes, err := FindRunnerTypes(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "main", pkgName)
+	assert.Equal(t, []string{"SetLabels"}, runnerTypes)
+
+	_, _, err = FindRunnerTypes(t.TempDir())
+	assert.ErrorContains(t, err, "no Go files found")
+}
+
+func TestCRD(t *testing.T) {
+	testcases := map[string]struct {
+		args         []string
+		expectedType string
+	}{
+		"detect the Runner struct": {
+			expectedType: "SetLabels",
+		},
+		"specify the Runner struct": {
+			args:         []string{"--type=Other"},
+			expectedType: "Other",
+		},
+	}
+	for name, test := range testcases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(crdTestFunction), 0600))
+
+			execCmdOutputFn = func(envs []string, name string, args ...string) ([]byte, error) {
+				assert.Equal(t, "go -C "+dir+" test -count=1 -run ^TestKfnGenerateCRD$ .",
+					strings.Join(append([]string{name}, args...), " "))
+				src, err := os.ReadFile(filepath.Join(dir, CRDGeneratorFile))
+				assert.NoError(t, err)
+				assert.Contains(t, string(src), "package main")
+				assert.Contains(t, string(src), "fn.ConfigCRD(&"+test.expectedType+"{}, docs)")
+				assert.Len(t, envs, 1)
+				output := strings.TrimPrefix(envs[0], CRDOutputEnvVar+"=")
+				return nil, os.WriteFile(output, []byte("kind: CustomResourceDefinition\n"), 0600)
+			}
+
+			r := NewCRDRunner(context.TODO())
+			var out bytes.Buffer
+			r.Command.SetOut(&out)
+			r.Command.SetArgs(append(test.args, dir))
+			err := r.Command.Execute()
+			assert.NoError(t, err)
+			assert.Equal(t, "kind: CustomResourceDefinition\n", out.String())
+			_, err = os.Stat(filepath.Join(dir, CRDGeneratorFile))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
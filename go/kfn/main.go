@@ -31,6 +31,7 @@ func main() {
 	ctx := context.Background()
 	cmd.AddCommand(commands.NewInitRunner(ctx).Command)
 	cmd.AddCommand(commands.NewBuildRunner(ctx).Command)
+	cmd.AddCommand(commands.NewCRDRunner(ctx).Command)
 	err = cmd.Execute()
 	if err != nil {
 		os.Exit(1)